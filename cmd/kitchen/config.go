@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"kitchen"
+	"kitchen/pkg/common/config"
+
+	"github.com/spf13/cobra"
+)
+
+// newConfigCommand creates the config command
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the service config",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dump",
+		Short: "Write the resolved config as JSON, masking secure values",
+		Long: "Write the resolved config as JSON, masking secure values. The config is\n" +
+			"not validated so an invalid config can be inspected",
+		Args: cobra.NoArgs,
+		RunE: runConfigDump,
+	})
+	return cmd
+}
+
+// runConfigDump writes the resolved config to stdout
+func runConfigDump(cmd *cobra.Command, _ []string) error {
+	c, err := config.Load(cmd.Flags())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Binding registers the fields tagged as secure so they are masked
+	var cfg kitchen.Config
+	if err := c.Bind(&cfg); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if _, err := c.WriteTo(cmd.OutOrStdout()); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout())
+	return err
+}
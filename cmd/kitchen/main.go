@@ -0,0 +1,44 @@
+// Command kitchen runs the kitchen service
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().ExecuteContext(context.Background()); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand creates the kitchen root command
+func newRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "kitchen",
+		Short:        "kitchen stores and serves posts",
+		SilenceUsage: true,
+	}
+
+	// Flags are named after their config keys so the config loader binds them
+	// with the same precedence as every other source. Defaults are registered
+	// with the loader, not here
+	flags := cmd.PersistentFlags()
+	flags.String("dd_env", "", "environment to run in, e.g. local, stg or prod")
+	flags.String("log_level", "", "log level")
+	flags.String("store_backend", "", "store backend, one of memory, dynamodb or sqlite")
+	flags.String("sqlite_path", "", "path of the sqlite database")
+	flags.String("dynamo_endpoint", "", "DynamoDB endpoint override, e.g. http://localhost:8000")
+	flags.String("dynamo_table", "", "DynamoDB table name")
+	flags.String("dynamo_region", "", "DynamoDB region")
+
+	cmd.AddCommand(
+		newServeCommand(),
+		newConfigCommand(),
+		newVersionCommand(),
+		newMigrateCommand(),
+	)
+	return cmd
+}
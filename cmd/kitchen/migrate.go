@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+
+	"kitchen"
+	"kitchen/internal/store"
+	"kitchen/pkg/common/logging"
+	"kitchen/pkg/service"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// newMigrateCommand creates the migrate command
+func newMigrateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Create or upgrade the store schema",
+		Long: "Create or upgrade the store schema. For sqlite the schema migrations are\n" +
+			"applied, for dynamodb the table and its indexes are created",
+		Args: cobra.NoArgs,
+		RunE: runMigrate,
+	}
+}
+
+// runMigrate migrates the configured store
+func runMigrate(cmd *cobra.Command, _ []string) error {
+	var cfg kitchen.Config
+	if err := service.LoadConfigForCommand(cmd, &cfg); err != nil {
+		return err
+	}
+	logger := logging.NewLogger("kitchen")
+
+	st, err := kitchen.NewStore(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	if hook, ok := st.(interface{ Shutdown(context.Context) error }); ok {
+		defer hook.Shutdown(cmd.Context())
+	}
+	migrator, ok := st.(store.Migrator)
+	if !ok {
+		logger.Info("store has no schema to migrate", zap.String("store_backend", cfg.StoreBackend))
+		return nil
+	}
+	if err := migrator.Migrate(cmd.Context()); err != nil {
+		return err
+	}
+	logger.Info("store migrated", zap.String("store_backend", cfg.StoreBackend))
+	return nil
+}
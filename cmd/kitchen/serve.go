@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+
+	"kitchen"
+	"kitchen/internal/manager"
+	"kitchen/internal/server"
+	"kitchen/pkg/common/metrics"
+	"kitchen/pkg/common/tracing"
+	"kitchen/pkg/service"
+	"kitchen/pkg/service/connect"
+	"kitchen/pkg/service/system"
+	"kitchen/proto/gen/kitchen/v1/kitchenv1connect"
+
+	"github.com/spf13/cobra"
+)
+
+// newServeCommand creates the serve command
+func newServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the kitchen service",
+		Args:  cobra.NoArgs,
+		RunE:  runServe,
+	}
+	flags := cmd.Flags()
+	flags.String("bind_address", "", "address to listen on")
+	flags.Int("grpc_port", 0, "port to serve the connect, gRPC and gRPC-Web APIs on")
+	flags.Int("http_port", 0, "port to serve HTTP on")
+	flags.Int("system_port", 0, "port to serve the health probes, profiles and config on")
+	flags.Bool("profiler_enabled", false, "serve the runtime profiles on the system port")
+	flags.String("metrics_address", "", "OTLP/gRPC collector to push metrics to, as host:port or a URL")
+	flags.String("trace_address", "", "OTLP/gRPC collector to export traces to, as host:port or a URL")
+	flags.Float64("trace_sample_rate", 0, "fraction of new traces to sample")
+	flags.StringSlice("valid_issuers", nil, "issuers bearer tokens are accepted from, authentication is disabled when empty")
+	flags.StringSlice("jwks_urls", nil, "key set URLs of the valid issuers by position, defaults to the issuer's /.well-known/jwks.json")
+	flags.String("jwt_audience", "", "audience bearer tokens must be issued for")
+	flags.Bool("authz_default_deny", false, "deny procedures without an authorization rule")
+	flags.String("tls_cert_file", "", "certificate to serve TLS with, h2c plaintext is served when empty")
+	flags.String("tls_key_file", "", "private key of the TLS certificate")
+	flags.String("tls_client_ca_file", "", "CA bundle client certificates are verified against, enabling mutual TLS")
+	flags.Bool("tls_require_client_cert", false, "reject callers without a verified client certificate")
+	flags.Duration("idempotency_key_ttl", 0, "how long responses to requests with an Idempotency-Key are replayed to retries")
+	flags.Bool("log_payloads", false, "log the request and response messages of unary calls in the access log")
+	flags.Duration("shutdown_drain_delay", 0, "how long to keep serving after a stop signal while reporting not-ready")
+	flags.Duration("shutdown_timeout", 0, "deadline for in-flight requests to complete when stopping")
+	return cmd
+}
+
+// runServe runs the service until it receives SIGINT or SIGTERM, then drains
+// and shuts it down
+func runServe(cmd *cobra.Command, _ []string) error {
+	var cfg kitchen.Config
+	if err := service.LoadConfigForCommand(cmd, &cfg); err != nil {
+		return err
+	}
+
+	// Export traces, flushing them once everything else has shut down
+	shutdownTracing, err := tracing.Configure(cmd.Context(), cfg.Base)
+	if err != nil {
+		return err
+	}
+
+	// Record metrics for scraping and pushing
+	meterProvider, metricsHandler, err := metrics.Configure(cmd.Context(), cfg.Base)
+	if err != nil {
+		return err
+	}
+
+	// Build the store, manager and handler
+	st, err := kitchen.NewStore(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	handler := server.NewServer(*manager.NewManager(st))
+	srv := connect.NewServer[kitchenv1connect.KitchenServiceHandler](cfg.Config, kitchenv1connect.NewKitchenServiceHandler, handler,
+		connect.WithMeterProvider(meterProvider),
+	)
+
+	// Let the store prepare itself before traffic arrives and clean up after
+	if hook, ok := st.(interface{ PreStart(context.Context) error }); ok {
+		srv.RegisterPreStartHook(hook.PreStart)
+	}
+	if hook, ok := st.(interface{ Shutdown(context.Context) error }); ok {
+		srv.RegisterShutdownHook(hook.Shutdown)
+	}
+	srv.RegisterShutdownHook(meterProvider.Shutdown, shutdownTracing)
+
+	// Serve the health probes, metrics, profiles and config on the system port. The
+	// service is ready once listening, while the store can be reached. The
+	// gRPC health service reports the same checks
+	sys := system.NewServer(cfg.Config)
+	sys.Handle("GET /metrics", metricsHandler)
+	runner := service.NewRunner(cfg.Config, srv, sys)
+	sys.RegisterReadinessCheck("server", runner.CheckReady)
+	srv.Health().RegisterCheck("", "server", runner.CheckReady)
+	if pinger, ok := st.(interface{ Ping(context.Context) error }); ok {
+		sys.RegisterReadinessCheck("store", pinger.Ping)
+		srv.Health().RegisterCheck("", "store", pinger.Ping)
+	}
+
+	// Serve until we are signalled to stop or the server fails
+	return runner.Run(cmd.Context())
+}
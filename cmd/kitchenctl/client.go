@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"kitchen/proto/gen/kitchen/v1/kitchenv1connect"
+
+	"connectrpc.com/connect"
+	"golang.org/x/net/http2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// The protocols the client can call the service with
+const (
+	protocolConnect = "connect"
+	protocolGRPC    = "grpc"
+	protocolGRPCWeb = "grpcweb"
+)
+
+// options are the options shared by every command
+type options struct {
+	addr     string
+	protocol string
+	headers  []string
+	output   string
+	timeout  time.Duration
+}
+
+// validate validates the options
+func (o *options) validate() error {
+	if _, err := url.Parse(o.addr); err != nil {
+		return fmt.Errorf("invalid addr %q: %w", o.addr, err)
+	}
+	switch o.protocol {
+	case protocolConnect, protocolGRPC, protocolGRPCWeb:
+	default:
+		return fmt.Errorf("unknown protocol %q, must be one of connect, grpc or grpcweb", o.protocol)
+	}
+	switch o.output {
+	case outputJSON, outputYAML, outputTable:
+	default:
+		return fmt.Errorf("unknown output %q, must be one of json, yaml or table", o.output)
+	}
+	_, err := o.header()
+	return err
+}
+
+// header parses the custom headers
+func (o *options) header() (http.Header, error) {
+	header := make(http.Header, len(o.headers))
+	for _, h := range o.headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, must be \"Name: value\"", h)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header, nil
+}
+
+// context returns the context to make a call with, applying the timeout
+func (o *options) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// client creates a kitchen service client using the selected protocol
+func (o *options) client() (kitchenv1connect.KitchenServiceClient, error) {
+	header, err := o.header()
+	if err != nil {
+		return nil, err
+	}
+	opts := []connect.ClientOption{connect.WithInterceptors(&headerInterceptor{header: header})}
+	switch o.protocol {
+	case protocolGRPC:
+		opts = append(opts, connect.WithGRPC())
+	case protocolGRPCWeb:
+		opts = append(opts, connect.WithGRPCWeb())
+	}
+	return kitchenv1connect.NewKitchenServiceClient(httpClient(o.addr), o.addr, opts...), nil
+}
+
+// httpClient creates the HTTP client for the address. Plain http addresses use
+// HTTP/2 without TLS so gRPC works against the server's h2c listener
+func httpClient(addr string) *http.Client {
+	if !strings.HasPrefix(addr, "http://") {
+		return http.DefaultClient
+	}
+	return &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		},
+	}
+}
+
+// headerInterceptor adds the custom headers to every request, where the
+// server's metadata interceptor makes them available to handlers
+type headerInterceptor struct {
+	header http.Header
+}
+
+// WrapUnary wraps a unary call adding the headers
+func (i *headerInterceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		for name, values := range i.header {
+			for _, value := range values {
+				request.Header().Add(name, value)
+			}
+		}
+		return fn(ctx, request)
+	}
+}
+
+// WrapStreamingClient wraps a streaming client call adding the headers
+func (i *headerInterceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := fn(ctx, spec)
+		for name, values := range i.header {
+			for _, value := range values {
+				conn.RequestHeader().Add(name, value)
+			}
+		}
+		return conn
+	}
+}
+
+// WrapStreamingHandler returns the handler unchanged
+func (i *headerInterceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return fn
+}
+
+// describeError adds the code and any field violations of a connect error to
+// its message
+func describeError(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", connectErr.Code(), connectErr.Message())
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			continue
+		}
+		if badRequest, ok := value.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				fmt.Fprintf(&b, "\n  %s: %s", violation.GetField(), violation.GetDescription())
+			}
+		}
+	}
+	return errors.New(b.String())
+}
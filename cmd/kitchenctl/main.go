@@ -0,0 +1,41 @@
+// Command kitchenctl is a command line client for the kitchen service
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().ExecuteContext(context.Background()); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand creates the kitchenctl root command
+func newRootCommand() *cobra.Command {
+	var opts options
+	cmd := &cobra.Command{
+		Use:          "kitchenctl",
+		Short:        "kitchenctl calls the kitchen service",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			return opts.validate()
+		},
+	}
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.addr, "addr", "http://localhost:50051", "base URL of the kitchen service")
+	flags.StringVar(&opts.protocol, "protocol", protocolConnect, "protocol to call the service with, one of connect, grpc or grpcweb")
+	flags.StringArrayVarP(&opts.headers, "header", "H", nil, `header to send with the request as "Name: value", may be repeated`)
+	flags.StringVarP(&opts.output, "output", "o", outputJSON, "output format, one of json, yaml or table")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "request timeout, zero for none")
+
+	cmd.AddCommand(
+		newCreatePostCommand(&opts),
+		newGetPostCommand(&opts),
+		newListCommand(&opts),
+	)
+	return cmd
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// The output formats
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+)
+
+// maxCaptionWidth is the widest a caption is printed in a table
+const maxCaptionWidth = 40
+
+// write writes the response message in the output format
+func write(w io.Writer, output string, msg proto.Message) error {
+	switch output {
+	case outputYAML:
+		return writeYAML(w, msg)
+	case outputTable:
+		return writeTable(w, msg)
+	default:
+		b, err := protojson.MarshalOptions{Multiline: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+}
+
+// writeYAML writes the message as YAML, using the same field names as the
+// JSON output
+func writeYAML(w io.Writer, msg proto.Message) error {
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeTable writes the message as a table
+func writeTable(w io.Writer, msg proto.Message) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	switch msg := msg.(type) {
+	case *kitchenv1.CreatePostResponse:
+		fmt.Fprintln(tw, "ID")
+		fmt.Fprintln(tw, msg.Id)
+	case *kitchenv1.GetPostResponse:
+		writePosts(tw, msg.Post)
+	case *kitchenv1.ListPostsResponse:
+		writePosts(tw, msg.Posts...)
+		if msg.NextPageToken != "" {
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "\nnext page token: %s\n", msg.NextPageToken)
+		}
+	default:
+		return fmt.Errorf("table output is not supported for %s", msg.ProtoReflect().Descriptor().FullName())
+	}
+	return tw.Flush()
+}
+
+// writePosts writes a row per post
+func writePosts(w io.Writer, posts ...*kitchenv1.Post) {
+	fmt.Fprintln(w, "ID\tUSER\tCREATED\tVERSION\tIMAGES\tCAPTION")
+	for _, post := range posts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n",
+			post.Id,
+			post.UserId,
+			post.CreatedAt.AsTime().Local().Format(time.DateTime),
+			post.Version,
+			len(post.ImageUrls),
+			truncate(post.Caption, maxCaptionWidth),
+		)
+	}
+}
+
+// truncate shortens the caption to a single line of at most width runes
+func truncate(s string, width int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s
+}
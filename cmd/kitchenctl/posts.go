@@ -0,0 +1,87 @@
+package main
+
+import (
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"connectrpc.com/connect"
+	"github.com/spf13/cobra"
+)
+
+// newCreatePostCommand creates the create-post command
+func newCreatePostCommand(opts *options) *cobra.Command {
+	var req kitchenv1.CreatePostRequest
+	cmd := &cobra.Command{
+		Use:   "create-post",
+		Short: "Create a post",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd.Context())
+			defer cancel()
+			resp, err := client.CreatePost(ctx, connect.NewRequest(&req))
+			if err != nil {
+				return describeError(err)
+			}
+			return write(cmd.OutOrStdout(), opts.output, resp.Msg)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&req.UserId, "user-id", "", "id of the user creating the post")
+	flags.StringVar(&req.Caption, "caption", "", "caption of the post")
+	flags.StringArrayVar(&req.ImageUrls, "image-url", nil, "URL of an image in the post, may be repeated")
+	return cmd
+}
+
+// newGetPostCommand creates the get-post command
+func newGetPostCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-post ID",
+		Short: "Get a post",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd.Context())
+			defer cancel()
+			resp, err := client.GetPost(ctx, connect.NewRequest(&kitchenv1.GetPostRequest{Id: args[0]}))
+			if err != nil {
+				return describeError(err)
+			}
+			return write(cmd.OutOrStdout(), opts.output, resp.Msg)
+		},
+	}
+}
+
+// newListCommand creates the list command
+func newListCommand(opts *options) *cobra.Command {
+	var req kitchenv1.ListPostsRequest
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"list-posts"},
+		Short:   "List posts, newest first",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd.Context())
+			defer cancel()
+			resp, err := client.ListPosts(ctx, connect.NewRequest(&req))
+			if err != nil {
+				return describeError(err)
+			}
+			return write(cmd.OutOrStdout(), opts.output, resp.Msg)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&req.UserId, "user-id", "", "only list the posts of this user")
+	flags.Int32Var(&req.PageSize, "page-size", 0, "maximum number of posts to list, the server default when zero")
+	flags.StringVar(&req.PageToken, "page-token", "", "next_page_token of a previous list")
+	return cmd
+}
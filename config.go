@@ -1 +1,66 @@
 package kitchen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kitchen/internal/store/dynamo"
+	"kitchen/internal/store/sqlite"
+	"kitchen/pkg/common/config"
+	"kitchen/pkg/service"
+)
+
+const (
+	// StoreBackendMemory selects the in-memory store, which is only allowed in
+	// local and test environments
+	StoreBackendMemory = "memory"
+	// StoreBackendDynamo selects the DynamoDB store
+	StoreBackendDynamo = "dynamodb"
+	// StoreBackendSQLite selects the embedded SQLite store
+	StoreBackendSQLite = "sqlite"
+)
+
+func init() {
+	config.RegisterDefault("store_backend", StoreBackendMemory)
+}
+
+// Ensure Config conforms to ValidatableConfig
+var _ service.ValidatableConfig = Config{}
+
+// Config is the kitchen service config
+type Config struct {
+	service.Config `config:",squash"`
+	StoreBackend   string        `config:"store_backend"`
+	Dynamo         dynamo.Config `config:",squash"`
+	SQLite         sqlite.Config `config:",squash"`
+}
+
+// Validate validates this config
+func (c Config) Validate(ctx context.Context) error {
+
+	// Validate the service config
+	if err := c.Config.Validate(ctx); err != nil {
+		return err
+	}
+
+	// Validate the store backend is usable in this environment
+	switch c.StoreBackend {
+	case StoreBackendMemory:
+		if c.Env != config.Local && c.Env != config.Test {
+			return fmt.Errorf("store backend %q is not allowed in env %q", c.StoreBackend, c.Env)
+		}
+	case StoreBackendDynamo:
+		if err := c.Dynamo.Validate(); err != nil {
+			return err
+		}
+	case StoreBackendSQLite:
+		if err := c.SQLite.Validate(); err != nil {
+			return err
+		}
+	case "":
+		return errors.New("store_backend is required")
+	default:
+		return fmt.Errorf("unknown store backend %q", c.StoreBackend)
+	}
+	return nil
+}
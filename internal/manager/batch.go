@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"kitchen/internal/store"
+	"kitchen/pkg/common/logging"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"go.uber.org/zap"
+)
+
+// batchGet reads the posts with the supplied ids. Posts that were found are
+// returned keyed by id, ids that failed individually have their error in the
+// errors map. An error is only returned when the batch as a whole failed
+func (m *Manager) batchGet(ctx context.Context, ids []string) (map[string]*kitchenv1.Post, map[string]error, error) {
+
+	// Use the store's own batch read when it has one
+	if batchGetter, ok := m.store.(store.BatchGetter); ok {
+		posts, err := batchGetter.BatchGetPosts(ctx, ids)
+		return posts, nil, err
+	}
+
+	// Otherwise fall back to concurrent reads, bounded so a large batch cannot
+	// overwhelm the store
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	posts := make(map[string]*kitchenv1.Post, len(ids))
+	errs := make(map[string]error)
+	sem := make(chan struct{}, MaxBatchConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			post, err := m.store.GetPost(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[id] = err
+				return
+			}
+			posts[id] = post
+		}(id)
+	}
+	wg.Wait()
+
+	// A cancelled request fails every read, report it once rather than per id
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return posts, errs, nil
+}
+
+// batchFailure describes why the post with the supplied id was not returned. A
+// missing post without an error was not found by a batch read
+func batchFailure(ctx context.Context, id string, err error) *kitchenv1.BatchGetPostsFailure {
+	if err == nil || errors.Is(err, store.ErrNotFound) {
+		return &kitchenv1.BatchGetPostsFailure{
+			Id:      id,
+			Reason:  kitchenv1.BatchGetPostsFailure_REASON_NOT_FOUND,
+			Message: "post not found",
+		}
+	}
+
+	// Unexpected errors are logged and hidden from the caller, as they are for
+	// single reads
+	logging.FromContext(ctx).Warn("failed to get post in batch", zap.String("id", id), zap.Error(err))
+	return &kitchenv1.BatchGetPostsFailure{
+		Id:      id,
+		Reason:  kitchenv1.BatchGetPostsFailure_REASON_INTERNAL,
+		Message: "internal error",
+	}
+}
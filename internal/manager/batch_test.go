@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kitchen/internal/store"
+	"kitchen/internal/store/memory"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+)
+
+// brokenID is an id the single read store fails to read
+const brokenID = "broken"
+
+// singleReadStore is a store that cannot batch reads, failing to read brokenID
+type singleReadStore struct {
+	store.Store
+}
+
+func (s singleReadStore) GetPost(ctx context.Context, id string) (*kitchenv1.Post, error) {
+	if id == brokenID {
+		return nil, errors.New("connection reset")
+	}
+	return s.Store.GetPost(ctx, id)
+}
+
+func TestBatchGetPostsFallback(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(singleReadStore{memory.NewStore()})
+	if _, ok := m.store.(store.BatchGetter); ok {
+		t.Fatal("the store batches reads, the fallback is not tested")
+	}
+
+	// Create more posts than are read concurrently
+	var ids []string
+	for i := 0; i < 2*MaxBatchConcurrency; i++ {
+		created, err := m.CreatePost(ctx, &kitchenv1.CreatePostRequest{UserId: "alice"})
+		if err != nil {
+			t.Fatalf("CreatePost failed: %v", err)
+		}
+		ids = append(ids, created.Id)
+	}
+
+	// Every id is reported in request order, as a post or as a failure
+	request := []string{ids[3], "missing", ids[0], brokenID}
+	request = append(request, ids[4:]...)
+	resp, err := m.BatchGetPosts(ctx, &kitchenv1.BatchGetPostsRequest{Ids: request})
+	if err != nil {
+		t.Fatalf("BatchGetPosts failed: %v", err)
+	}
+	var got []string
+	for _, post := range resp.Posts {
+		got = append(got, post.Id)
+	}
+	want := append([]string{ids[3], ids[0]}, ids[4:]...)
+	if len(got) != len(want) {
+		t.Fatalf("posts = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("posts = %v, want %v", got, want)
+		}
+	}
+
+	// A missing post is not found, and a failed read is internal
+	if len(resp.Failures) != 2 {
+		t.Fatalf("failures = %v, want 2", resp.Failures)
+	}
+	for i, want := range []struct {
+		id     string
+		reason kitchenv1.BatchGetPostsFailure_Reason
+	}{
+		{"missing", kitchenv1.BatchGetPostsFailure_REASON_NOT_FOUND},
+		{brokenID, kitchenv1.BatchGetPostsFailure_REASON_INTERNAL},
+	} {
+		if failure := resp.Failures[i]; failure.Id != want.id || failure.Reason != want.reason {
+			t.Errorf("failure %d = %v, want %s %v", i, failure, want.id, want.reason)
+		}
+	}
+}
+
+func TestBatchGetPostsFallbackCancelled(t *testing.T) {
+	m := NewManager(singleReadStore{memory.NewStore()})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A cancelled batch fails as a whole rather than per id
+	if _, _, err := m.batchGet(ctx, []string{"one", "two"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("batchGet = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,19 @@
+package manager
+
+import "time"
+
+// Clock provides the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is an adapter to allow the use of ordinary functions as a Clock
+type ClockFunc func() time.Time
+
+// Now calls f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the Clock backed by time.Now
+var SystemClock Clock = ClockFunc(time.Now)
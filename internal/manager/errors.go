@@ -0,0 +1,32 @@
+package manager
+
+import (
+	"errors"
+
+	"kitchen/internal/store"
+	common_errors "kitchen/pkg/common/errors"
+)
+
+// translate converts the errors returned by the store to domain errors. Errors
+// the store does not classify are returned unchanged
+func translate(err error) error {
+	var notFound *store.NotFoundError
+	var alreadyExists *store.AlreadyExistsError
+	switch {
+	case errors.As(err, &notFound):
+		return common_errors.NotFound(notFound.Resource, notFound.ID)
+	case errors.As(err, &alreadyExists):
+		return common_errors.AlreadyExists(alreadyExists.Resource, alreadyExists.ID).WithCause(err)
+	case errors.Is(err, store.ErrVersionMismatch):
+		return common_errors.Conflict("post was modified concurrently, reload it and retry").
+			WithReason("VERSION_MISMATCH").
+			WithCause(err)
+	case errors.Is(err, store.ErrInvalidPageToken):
+		return common_errors.InvalidArgument("invalid page token", common_errors.FieldViolation{
+			Field:       "page_token",
+			Description: "must be a next_page_token returned by a previous call with the same filter",
+		}).WithCause(err)
+	default:
+		return err
+	}
+}
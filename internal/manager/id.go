@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// IDGenerator generates post ids. The supplied time is the creation time of the
+// post, generators producing sortable ids should embed it
+type IDGenerator interface {
+	NewID(now time.Time) (string, error)
+}
+
+// IDGeneratorFunc is an adapter to allow the use of ordinary functions as an
+// IDGenerator
+type IDGeneratorFunc func(now time.Time) (string, error)
+
+// NewID calls f(now)
+func (f IDGeneratorFunc) NewID(now time.Time) (string, error) {
+	return f(now)
+}
+
+// ULIDGenerator generates ULIDs, which sort by their creation time to the
+// millisecond. Ids generated within the same millisecond are monotonically
+// increasing
+type ULIDGenerator struct {
+	mu      sync.Mutex
+	entropy io.Reader
+}
+
+// NewULIDGenerator creates a new ULID generator
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{entropy: ulid.Monotonic(rand.Reader, 0)}
+}
+
+// NewID generates a new ULID for the supplied time
+func (g *ULIDGenerator) NewID(now time.Time) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	id, err := ulid.New(ulid.Timestamp(now), g.entropy)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
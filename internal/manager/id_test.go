@@ -0,0 +1,39 @@
+package manager
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestULIDGeneratorSameMillisecond(t *testing.T) {
+	g := NewULIDGenerator()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Ids generated within the same millisecond sort in creation order
+	ids := make([]string, 1000)
+	for i := range ids {
+		id, err := g.NewID(now.Add(time.Duration(i%1000) * time.Microsecond))
+		if err != nil {
+			t.Fatalf("NewID failed: %v", err)
+		}
+		ids[i] = id
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Error("ids generated within the same millisecond are not sorted")
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] == ids[i-1] {
+			t.Fatalf("id %s generated twice", ids[i])
+		}
+	}
+
+	// and before the ids of the next millisecond
+	next, err := g.NewID(now.Add(time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewID failed: %v", err)
+	}
+	if next <= ids[len(ids)-1] {
+		t.Errorf("id %s of the next millisecond sorts before %s", next, ids[len(ids)-1])
+	}
+}
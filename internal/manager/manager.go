@@ -2,27 +2,240 @@ package manager
 
 import (
 	"context"
+	"fmt"
 
-	kitchenv1 "kitchen/proto/gen/kitchen/v1"
 	"kitchen/internal/store"
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/service/authz"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultPageSize is the page size used when a list request does not
+	// specify one
+	DefaultPageSize = 25
+	// MaxPageSize is the largest page size a list request may ask for
+	MaxPageSize = 100
+	// MaxBatchConcurrency is the number of concurrent reads used to batch get
+	// posts from a store that cannot batch reads itself
+	MaxBatchConcurrency = 10
 )
 
+// mutablePostFields are the post fields an update is allowed to modify
+var mutablePostFields = []string{"caption", "image_urls"}
+
+// Manager implements the post business logic. It owns post ids, timestamps and
+// versions so every store behaves the same
 type Manager struct {
-	store store.Store
+	store       store.Store
+	idGenerator IDGenerator
+	clock       Clock
 }
 
-func NewManager(store store.Store) *Manager {
-	return &Manager{store: store}
+func NewManager(store store.Store, opts ...Option) *Manager {
+
+	// Parse the configuration options
+	options := options{
+		clock: SystemClock,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.idGenerator == nil {
+		options.idGenerator = NewULIDGenerator()
+	}
+
+	return &Manager{
+		store:       store,
+		idGenerator: options.idGenerator,
+		clock:       options.clock,
+	}
 }
 
 func (m *Manager) CreatePost(ctx context.Context, req *kitchenv1.CreatePostRequest) (*kitchenv1.CreatePostResponse, error) {
-	return m.store.CreatePost(ctx, req)
+	if err := authz.CheckOwner(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	now := m.clock.Now()
+	id, err := m.idGenerator.NewID(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate post id: %w", err)
+	}
+	post := &kitchenv1.Post{
+		Id:        id,
+		Caption:   req.Caption,
+		UserId:    req.UserId,
+		ImageUrls: req.ImageUrls,
+		CreatedAt: timestamppb.New(now),
+		UpdatedAt: timestamppb.New(now),
+		Version:   1,
+	}
+	if err := m.store.CreatePost(ctx, post); err != nil {
+		return nil, translate(err)
+	}
+	return &kitchenv1.CreatePostResponse{Id: id}, nil
 }
 
 func (m *Manager) GetPost(ctx context.Context, req *kitchenv1.GetPostRequest) (*kitchenv1.GetPostResponse, error) {
 	post, err := m.store.GetPost(ctx, req.Id)
 	if err != nil {
+		return nil, translate(err)
+	}
+	if err := authz.CheckOwner(ctx, post.UserId); err != nil {
 		return nil, err
 	}
 	return &kitchenv1.GetPostResponse{Post: post}, nil
 }
+
+func (m *Manager) BatchGetPosts(ctx context.Context, req *kitchenv1.BatchGetPostsRequest) (*kitchenv1.BatchGetPostsResponse, error) {
+	posts, errs, err := m.batchGet(ctx, req.Ids)
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	// Report every id in request order, either as a post or as a failure.
+	// Posts of other owners are reported as not found when the call is
+	// restricted to an owner
+	resp := new(kitchenv1.BatchGetPostsResponse)
+	for _, id := range req.Ids {
+		if post, ok := posts[id]; ok && authz.CheckOwner(ctx, post.UserId) == nil {
+			resp.Posts = append(resp.Posts, post)
+			continue
+		}
+		resp.Failures = append(resp.Failures, batchFailure(ctx, id, errs[id]))
+	}
+	return resp, nil
+}
+
+func (m *Manager) ListPosts(ctx context.Context, req *kitchenv1.ListPostsRequest) (*kitchenv1.ListPostsResponse, error) {
+
+	// A call restricted to an owner lists the owner's posts, and may not list
+	// those of another user
+	userID := req.UserId
+	if owner, ok := authz.OwnerFromContext(ctx); ok && userID == "" {
+		userID = owner
+	}
+	if err := authz.CheckOwner(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// Validate the page token up front so every store sees a well formed one
+	if req.PageToken != "" {
+		if _, err := store.DecodePageToken(req.PageToken, userID); err != nil {
+			return nil, translate(err)
+		}
+	}
+	resp, err := m.store.ListPosts(ctx, &kitchenv1.ListPostsRequest{
+		UserId:    userID,
+		PageSize:  pageSize(req.PageSize),
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return resp, nil
+}
+
+func (m *Manager) UpdatePost(ctx context.Context, req *kitchenv1.UpdatePostRequest) (*kitchenv1.UpdatePostResponse, error) {
+
+	// Work out which fields are being updated
+	paths, err := updatePaths(req.UpdateMask)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the current post, failing fast on a post the caller may not update
+	// or a stale version. The store re-checks the version when writing so
+	// concurrent writers still conflict
+	current, err := m.store.GetPost(ctx, req.GetPost().GetId())
+	if err != nil {
+		return nil, translate(err)
+	}
+	if err := authz.CheckOwner(ctx, current.UserId); err != nil {
+		return nil, err
+	}
+	if current.Version != req.GetPost().GetVersion() {
+		return nil, translate(store.ErrVersionMismatch)
+	}
+
+	// Apply the masked fields and write the post back
+	updated := proto.Clone(current).(*kitchenv1.Post)
+	for _, path := range paths {
+		switch path {
+		case "caption":
+			updated.Caption = req.Post.Caption
+		case "image_urls":
+			updated.ImageUrls = req.Post.ImageUrls
+		}
+	}
+	updated.UpdatedAt = timestamppb.New(m.clock.Now())
+	post, err := m.store.UpdatePost(ctx, updated)
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &kitchenv1.UpdatePostResponse{Post: post}, nil
+}
+
+func (m *Manager) DeletePost(ctx context.Context, req *kitchenv1.DeletePostRequest) (*kitchenv1.DeletePostResponse, error) {
+
+	// Check the caller owns the post when the call is restricted to an owner,
+	// the owner of a post never changes so it cannot race the delete
+	if _, ok := authz.OwnerFromContext(ctx); ok {
+		current, err := m.store.GetPost(ctx, req.Id)
+		if err != nil {
+			return nil, translate(err)
+		}
+		if err := authz.CheckOwner(ctx, current.UserId); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.store.DeletePost(ctx, req.Id, req.Version); err != nil {
+		return nil, translate(err)
+	}
+	return &kitchenv1.DeletePostResponse{}, nil
+}
+
+// updatePaths returns the normalized set of paths to update, defaulting to all
+// mutable fields when the mask is empty
+func updatePaths(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return mutablePostFields, nil
+	}
+	mask = proto.Clone(mask).(*fieldmaskpb.FieldMask)
+	mask.Normalize()
+	for _, path := range mask.Paths {
+		if !isMutable(path) {
+			return nil, common_errors.InvalidArgument("invalid update mask", common_errors.FieldViolation{
+				Field:       "update_mask",
+				Description: fmt.Sprintf("field %q cannot be updated", path),
+			})
+		}
+	}
+	return mask.Paths, nil
+}
+
+// isMutable returns if the supplied path names a mutable post field
+func isMutable(path string) bool {
+	for _, field := range mutablePostFields {
+		if path == field {
+			return true
+		}
+	}
+	return false
+}
+
+// pageSize normalizes the requested page size
+func pageSize(size int32) int32 {
+	switch {
+	case size <= 0:
+		return DefaultPageSize
+	case size > MaxPageSize:
+		return MaxPageSize
+	default:
+		return size
+	}
+}
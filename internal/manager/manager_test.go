@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitchen/internal/store/memory"
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/service/authz"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+)
+
+func TestUpdatePostSetsUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewManager(memory.NewStore(), WithClock(ClockFunc(func() time.Time {
+		return now
+	})))
+
+	created, err := m.CreatePost(ctx, &kitchenv1.CreatePostRequest{UserId: "user", Caption: "before"})
+	if err != nil {
+		t.Fatalf("CreatePost failed: %v", err)
+	}
+	createdAt := now
+
+	now = now.Add(time.Hour)
+	resp, err := m.UpdatePost(ctx, &kitchenv1.UpdatePostRequest{
+		Post: &kitchenv1.Post{Id: created.Id, Caption: "after", Version: 1},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePost failed: %v", err)
+	}
+	if got := resp.Post.UpdatedAt.AsTime(); !got.Equal(now) {
+		t.Errorf("updated_at = %v, want %v", got, now)
+	}
+	if got := resp.Post.CreatedAt.AsTime(); !got.Equal(createdAt) {
+		t.Errorf("created_at = %v, want %v", got, createdAt)
+	}
+
+	// The stored post carries the new timestamp too
+	got, err := m.GetPost(ctx, &kitchenv1.GetPostRequest{Id: created.Id})
+	if err != nil {
+		t.Fatalf("GetPost failed: %v", err)
+	}
+	if !got.Post.UpdatedAt.AsTime().Equal(now) {
+		t.Errorf("stored updated_at = %v, want %v", got.Post.UpdatedAt.AsTime(), now)
+	}
+}
+
+func TestOwnerRestrictedCalls(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(memory.NewStore())
+	created, err := m.CreatePost(ctx, &kitchenv1.CreatePostRequest{UserId: "alice", Caption: "mine"})
+	if err != nil {
+		t.Fatalf("CreatePost failed: %v", err)
+	}
+
+	// Another caller can neither update nor delete the post, whatever user
+	// id they claim in the request
+	mallory := authz.NewOwnerContext(ctx, "mallory")
+	_, err = m.UpdatePost(mallory, &kitchenv1.UpdatePostRequest{
+		Post: &kitchenv1.Post{Id: created.Id, UserId: "mallory", Caption: "theirs", Version: 1},
+	})
+	if common_errors.KindOf(err) != common_errors.KindPermissionDenied {
+		t.Errorf("UpdatePost by another caller = %v, want PermissionDenied", err)
+	}
+	_, err = m.DeletePost(mallory, &kitchenv1.DeletePostRequest{Id: created.Id, Version: 1})
+	if common_errors.KindOf(err) != common_errors.KindPermissionDenied {
+		t.Errorf("DeletePost by another caller = %v, want PermissionDenied", err)
+	}
+
+	// The owner can
+	alice := authz.NewOwnerContext(ctx, "alice")
+	if _, err := m.UpdatePost(alice, &kitchenv1.UpdatePostRequest{
+		Post: &kitchenv1.Post{Id: created.Id, Caption: "still mine", Version: 1},
+	}); err != nil {
+		t.Fatalf("UpdatePost by the owner failed: %v", err)
+	}
+	if _, err := m.DeletePost(alice, &kitchenv1.DeletePostRequest{Id: created.Id, Version: 2}); err != nil {
+		t.Fatalf("DeletePost by the owner failed: %v", err)
+	}
+}
+
+// newOwnedPosts creates a manager holding posts of alice and bob, returning
+// their ids by owner
+func newOwnedPosts(t *testing.T) (*Manager, map[string]string) {
+	t.Helper()
+	m := NewManager(memory.NewStore())
+	ids := make(map[string]string)
+	for _, owner := range []string{"alice", "bob"} {
+		created, err := m.CreatePost(context.Background(), &kitchenv1.CreatePostRequest{UserId: owner, Caption: owner})
+		if err != nil {
+			t.Fatalf("CreatePost failed: %v", err)
+		}
+		ids[owner] = created.Id
+	}
+	return m, ids
+}
+
+func TestOwnerRestrictedCreatePost(t *testing.T) {
+	m := NewManager(memory.NewStore())
+	alice := authz.NewOwnerContext(context.Background(), "alice")
+	if _, err := m.CreatePost(alice, &kitchenv1.CreatePostRequest{UserId: "bob"}); common_errors.KindOf(err) != common_errors.KindPermissionDenied {
+		t.Errorf("CreatePost for another user = %v, want PermissionDenied", err)
+	}
+	if _, err := m.CreatePost(alice, &kitchenv1.CreatePostRequest{UserId: "alice"}); err != nil {
+		t.Errorf("CreatePost for the caller failed: %v", err)
+	}
+}
+
+func TestOwnerRestrictedGetPost(t *testing.T) {
+	m, ids := newOwnedPosts(t)
+	alice := authz.NewOwnerContext(context.Background(), "alice")
+	if _, err := m.GetPost(alice, &kitchenv1.GetPostRequest{Id: ids["bob"]}); common_errors.KindOf(err) != common_errors.KindPermissionDenied {
+		t.Errorf("GetPost of another user's post = %v, want PermissionDenied", err)
+	}
+	if _, err := m.GetPost(alice, &kitchenv1.GetPostRequest{Id: ids["alice"]}); err != nil {
+		t.Errorf("GetPost of the caller's post failed: %v", err)
+	}
+}
+
+func TestOwnerRestrictedBatchGetPosts(t *testing.T) {
+	m, ids := newOwnedPosts(t)
+	alice := authz.NewOwnerContext(context.Background(), "alice")
+	resp, err := m.BatchGetPosts(alice, &kitchenv1.BatchGetPostsRequest{Ids: []string{ids["bob"], ids["alice"]}})
+	if err != nil {
+		t.Fatalf("BatchGetPosts failed: %v", err)
+	}
+	if len(resp.Posts) != 1 || resp.Posts[0].Id != ids["alice"] {
+		t.Errorf("posts = %v, want only the caller's", resp.Posts)
+	}
+	if len(resp.Failures) != 1 || resp.Failures[0].Id != ids["bob"] ||
+		resp.Failures[0].Reason != kitchenv1.BatchGetPostsFailure_REASON_NOT_FOUND {
+		t.Errorf("failures = %v, want another user's post not found", resp.Failures)
+	}
+}
+
+func TestOwnerRestrictedListPosts(t *testing.T) {
+	m, ids := newOwnedPosts(t)
+	alice := authz.NewOwnerContext(context.Background(), "alice")
+	if _, err := m.ListPosts(alice, &kitchenv1.ListPostsRequest{UserId: "bob"}); common_errors.KindOf(err) != common_errors.KindPermissionDenied {
+		t.Errorf("ListPosts of another user = %v, want PermissionDenied", err)
+	}
+
+	// Listing without a user lists the caller's posts rather than the feed
+	for _, userID := range []string{"", "alice"} {
+		resp, err := m.ListPosts(alice, &kitchenv1.ListPostsRequest{UserId: userID})
+		if err != nil {
+			t.Fatalf("ListPosts of %q failed: %v", userID, err)
+		}
+		if len(resp.Posts) != 1 || resp.Posts[0].Id != ids["alice"] {
+			t.Errorf("ListPosts of %q = %v, want only the caller's post", userID, resp.Posts)
+		}
+	}
+}
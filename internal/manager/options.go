@@ -0,0 +1,26 @@
+package manager
+
+// Option is a configuration option
+type Option func(*options)
+
+// options configuration options
+type options struct {
+	idGenerator IDGenerator
+	clock       Clock
+}
+
+// WithIDGenerator specifies the generator used to assign post ids, defaults to
+// ULIDs
+func WithIDGenerator(idGenerator IDGenerator) Option {
+	return func(options *options) {
+		options.idGenerator = idGenerator
+	}
+}
+
+// WithClock specifies the clock used to timestamp posts, defaults to the system
+// clock
+func WithClock(clock Clock) Option {
+	return func(options *options) {
+		options.clock = clock
+	}
+}
@@ -34,3 +34,35 @@ func (s *Server) GetPost(ctx context.Context, req *connect.Request[kitchenv1.Get
 	}
 	return connect.NewResponse(resp), nil
 }
+
+func (s *Server) BatchGetPosts(ctx context.Context, req *connect.Request[kitchenv1.BatchGetPostsRequest]) (*connect.Response[kitchenv1.BatchGetPostsResponse], error) {
+	resp, err := s.manager.BatchGetPosts(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *Server) ListPosts(ctx context.Context, req *connect.Request[kitchenv1.ListPostsRequest]) (*connect.Response[kitchenv1.ListPostsResponse], error) {
+	resp, err := s.manager.ListPosts(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *Server) UpdatePost(ctx context.Context, req *connect.Request[kitchenv1.UpdatePostRequest]) (*connect.Response[kitchenv1.UpdatePostResponse], error) {
+	resp, err := s.manager.UpdatePost(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *Server) DeletePost(ctx context.Context, req *connect.Request[kitchenv1.DeletePostRequest]) (*connect.Response[kitchenv1.DeletePostResponse], error) {
+	resp, err := s.manager.DeletePost(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
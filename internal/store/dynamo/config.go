@@ -0,0 +1,46 @@
+package dynamo
+
+import (
+	"errors"
+	"kitchen/pkg/common/config"
+)
+
+func init() {
+	config.RegisterDefault("dynamo_table", "kitchen")
+	config.RegisterDefault("dynamo_region", "us-east-1")
+	config.RegisterDefault("dynamo_feed_shards", 1)
+}
+
+// Config is the DynamoDB store config
+type Config struct {
+	// Endpoint overrides the DynamoDB endpoint, for example
+	// http://localhost:8000 when using dynamodb-local
+	Endpoint string `config:"dynamo_endpoint"`
+	Table    string `config:"dynamo_table"`
+	Region   string `config:"dynamo_region"`
+	// AccessKeyID and SecretAccessKey supply static credentials, when unset the
+	// default AWS credential chain is used
+	AccessKeyID     string `config:"dynamo_access_key_id"`
+	SecretAccessKey string `config:"dynamo_secret_access_key,secure"`
+	// CreateTable creates the table and its indexes on start when they do not
+	// already exist
+	CreateTable bool `config:"dynamo_create_table"`
+	// FeedShards is the number of partitions the feed index is spread over.
+	// Every listing of the feed queries each of them, and posts are assigned
+	// a shard when written, so set it before storing posts in a new table
+	FeedShards int `config:"dynamo_feed_shards"`
+}
+
+// Validate validates this config
+func (c Config) Validate() error {
+	if c.Table == "" {
+		return errors.New("dynamo_table is required")
+	}
+	if c.Region == "" {
+		return errors.New("dynamo_region is required")
+	}
+	if c.FeedShards < 1 {
+		return errors.New("dynamo_feed_shards must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var _ API = (*fakeAPI)(nil)
+
+// indexKeys are the key attributes of each index, the table's own keys are
+// under the empty name
+var indexKeys = map[string][2]string{
+	"":        {attrPK, attrSK},
+	IndexUser: {attrGSI1PK, attrGSI1SK},
+	IndexFeed: {attrGSI2PK, attrGSI2SK},
+}
+
+// clausePattern matches the clauses of the condition and key condition
+// expressions the store writes
+var clausePattern = regexp.MustCompile(`^(?:(attribute_exists|attribute_not_exists)\((#\w+)\)|(#\w+) (=|<) (:\w+))$`)
+
+// fakeAPI is an in-process stand-in for DynamoDB holding a single table. It
+// understands the expressions the store writes, not the full expression
+// language
+type fakeAPI struct {
+	mu      sync.Mutex
+	created bool
+	items   map[string]map[string]types.AttributeValue
+}
+
+// newFakeAPI creates an empty fake without a table
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func (f *fakeAPI) DescribeTable(_ context.Context, in *dynamodb.DescribeTableInput, _ ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.created {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("table not found")}
+	}
+	return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+		TableName:   in.TableName,
+		TableStatus: types.TableStatusActive,
+	}}, nil
+}
+
+func (f *fakeAPI) CreateTable(_ context.Context, in *dynamodb.CreateTableInput, _ ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.created {
+		return nil, &types.ResourceInUseException{Message: aws.String("table exists")}
+	}
+	f.created = true
+	return &dynamodb.CreateTableOutput{TableDescription: &types.TableDescription{TableName: in.TableName}}, nil
+}
+
+func (f *fakeAPI) GetItem(_ context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: copyItem(f.items[itemKey(in.Key)])}, nil
+}
+
+func (f *fakeAPI) BatchGetItem(_ context.Context, in *dynamodb.BatchGetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	responses := make(map[string][]map[string]types.AttributeValue, len(in.RequestItems))
+	for table, request := range in.RequestItems {
+		for _, key := range request.Keys {
+			if item, ok := f.items[itemKey(key)]; ok {
+				responses[table] = append(responses[table], copyItem(item))
+			}
+		}
+	}
+	return &dynamodb.BatchGetItemOutput{Responses: responses}, nil
+}
+
+func (f *fakeAPI) PutItem(_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := itemKey(in.Item)
+	current := f.items[key]
+	if err := checkCondition(in.ConditionExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues, current, types.ReturnValuesOnConditionCheckFailureNone); err != nil {
+		return nil, err
+	}
+	f.items[key] = copyItem(in.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeAPI) UpdateItem(_ context.Context, in *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := itemKey(in.Key)
+	current := f.items[key]
+	if err := checkCondition(in.ConditionExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues, current, in.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, err
+	}
+
+	// Only SET actions of values are supported
+	updated := copyItem(current)
+	if updated == nil {
+		updated = copyItem(in.Key)
+	}
+	actions, ok := strings.CutPrefix(aws.ToString(in.UpdateExpression), "SET ")
+	if !ok {
+		return nil, fmt.Errorf("unsupported update expression %q", aws.ToString(in.UpdateExpression))
+	}
+	for _, action := range strings.Split(actions, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(action), " = ")
+		if !ok {
+			return nil, fmt.Errorf("unsupported update action %q", action)
+		}
+		updated[in.ExpressionAttributeNames[name]] = in.ExpressionAttributeValues[value]
+	}
+	f.items[key] = updated
+	return &dynamodb.UpdateItemOutput{Attributes: copyItem(updated)}, nil
+}
+
+func (f *fakeAPI) DeleteItem(_ context.Context, in *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := itemKey(in.Key)
+	if err := checkCondition(in.ConditionExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues, f.items[key], in.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, err
+	}
+	delete(f.items, key)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (f *fakeAPI) Query(_ context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys, ok := indexKeys[aws.ToString(in.IndexName)]
+	if !ok {
+		return nil, fmt.Errorf("unknown index %q", aws.ToString(in.IndexName))
+	}
+	pkAttr, skAttr := keys[0], keys[1]
+
+	// Collect the matching items in index order
+	var matched []map[string]types.AttributeValue
+	for _, item := range f.items {
+		if _, ok := item[pkAttr]; !ok {
+			continue
+		}
+		ok, err := evaluate(aws.ToString(in.KeyConditionExpression), in.ExpressionAttributeNames, in.ExpressionAttributeValues, item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+	forward := in.ScanIndexForward == nil || *in.ScanIndexForward
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := stringValue(matched[i][skAttr]), stringValue(matched[j][skAttr])
+		if forward {
+			return a < b
+		}
+		return a > b
+	})
+
+	// Resume after the start key and stop at the limit
+	if start := in.ExclusiveStartKey; len(start) > 0 {
+		for i, item := range matched {
+			if itemKey(item) == itemKey(start) {
+				matched = matched[i+1:]
+				break
+			}
+		}
+	}
+	out := new(dynamodb.QueryOutput)
+	if limit := int(aws.ToInt32(in.Limit)); limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+		last := matched[len(matched)-1]
+		out.LastEvaluatedKey = map[string]types.AttributeValue{
+			attrPK: last[attrPK], attrSK: last[attrSK], pkAttr: last[pkAttr], skAttr: last[skAttr],
+		}
+	}
+	for _, item := range matched {
+		out.Items = append(out.Items, copyItem(item))
+	}
+	out.Count = int32(len(out.Items))
+	return out, nil
+}
+
+// checkCondition evaluates the condition expression against the current item,
+// returning a ConditionalCheckFailedException when it does not hold
+func checkCondition(expression *string, names map[string]string, values map[string]types.AttributeValue, current map[string]types.AttributeValue, onFailure types.ReturnValuesOnConditionCheckFailure) error {
+	if expression == nil {
+		return nil
+	}
+	ok, err := evaluate(*expression, names, values, current)
+	if err != nil || ok {
+		return err
+	}
+	failed := &types.ConditionalCheckFailedException{Message: aws.String("the conditional request failed")}
+	if onFailure == types.ReturnValuesOnConditionCheckFailureAllOld {
+		failed.Item = copyItem(current)
+	}
+	return failed
+}
+
+// evaluate evaluates an expression of clauses joined by AND against the item
+func evaluate(expression string, names map[string]string, values map[string]types.AttributeValue, item map[string]types.AttributeValue) (bool, error) {
+	for _, clause := range strings.Split(expression, " AND ") {
+		match := clausePattern.FindStringSubmatch(strings.TrimSpace(clause))
+		if match == nil {
+			return false, fmt.Errorf("unsupported expression %q", expression)
+		}
+		if match[1] != "" {
+			_, exists := item[names[match[2]]]
+			if exists != (match[1] == "attribute_exists") {
+				return false, nil
+			}
+			continue
+		}
+		actual, ok := item[names[match[3]]]
+		if !ok {
+			return false, nil
+		}
+		expected := values[match[5]]
+		switch match[4] {
+		case "=":
+			if !reflect.DeepEqual(actual, expected) {
+				return false, nil
+			}
+		case "<":
+			if stringValue(actual) >= stringValue(expected) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// itemKey returns the table key of the item as a map key
+func itemKey(item map[string]types.AttributeValue) string {
+	return stringValue(item[attrPK]) + "\x00" + stringValue(item[attrSK])
+}
+
+// stringValue returns the value of a string attribute
+func stringValue(av types.AttributeValue) string {
+	if s, ok := av.(*types.AttributeValueMemberS); ok {
+		return s.Value
+	}
+	return ""
+}
+
+// copyItem returns a shallow copy of the item, attribute values are never
+// modified in place
+func copyItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if item == nil {
+		return nil
+	}
+	c := make(map[string]types.AttributeValue, len(item))
+	for k, v := range item {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,553 @@
+package dynamo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"kitchen/internal/store"
+	"kitchen/pkg/common/logging"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LoggerName the logger name to use for the store
+const LoggerName = "store.dynamo"
+
+// The store uses a single table. Posts are keyed by id, the user index lists a
+// user's posts and the feed index lists every post, both newest first. A
+// single feed partition takes every write and is throttled once the write rate
+// exceeds what one partition sustains, about 1000 writes per second, so the
+// feed is spread over Config.FeedShards partitions by post id
+const (
+	attrPK     = "pk"
+	attrSK     = "sk"
+	attrGSI1PK = "gsi1pk"
+	attrGSI1SK = "gsi1sk"
+	attrGSI2PK = "gsi2pk"
+	attrGSI2SK = "gsi2sk"
+
+	// IndexUser is the GSI listing a user's posts
+	IndexUser = "gsi1"
+	// IndexFeed is the GSI listing all posts
+	IndexFeed = "gsi2"
+
+	postPrefix = "POST#"
+	userPrefix = "USER#"
+	postSK     = "POST"
+	feedPK     = "FEED"
+)
+
+// maxBatchGetKeys is the most keys a single BatchGetItem call may request
+const maxBatchGetKeys = 100
+
+var (
+	_ store.Store       = (*Store)(nil)
+	_ store.BatchGetter = (*Store)(nil)
+	_ store.Migrator    = (*Store)(nil)
+)
+
+// API is the subset of the DynamoDB client used by the store. It allows an
+// in-process fake to stand in for DynamoDB in tests
+type API interface {
+	GetItem(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	BatchGetItem(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	PutItem(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	DescribeTable(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+}
+
+// item is the DynamoDB representation of a post
+type item struct {
+	PK        string   `dynamodbav:"pk"`
+	SK        string   `dynamodbav:"sk"`
+	GSI1PK    string   `dynamodbav:"gsi1pk"`
+	GSI1SK    string   `dynamodbav:"gsi1sk"`
+	GSI2PK    string   `dynamodbav:"gsi2pk"`
+	GSI2SK    string   `dynamodbav:"gsi2sk"`
+	ID        string   `dynamodbav:"id"`
+	UserID    string   `dynamodbav:"user_id"`
+	Caption   string   `dynamodbav:"caption"`
+	ImageURLs []string `dynamodbav:"image_urls"`
+	CreatedAt int64    `dynamodbav:"created_at"`
+	UpdatedAt int64    `dynamodbav:"updated_at"`
+	Version   int64    `dynamodbav:"version"`
+}
+
+// Store is a store.Store backed by DynamoDB
+type Store struct {
+	api    API
+	cfg    Config
+	logger *zap.Logger
+}
+
+// NewClient creates a DynamoDB client from the supplied config
+func NewClient(ctx context.Context, cfg Config) (*dynamodb.Client, error) {
+	awsCfg, err := loadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	return dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+	}), nil
+}
+
+// loadAWSConfig loads the AWS config, using static credentials when configured
+func loadAWSConfig(ctx context.Context, cfg Config) (aws.Config, error) {
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" {
+		provider := credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")
+		opts = append(opts, awsconfig.WithCredentialsProvider(provider))
+	}
+	return awsconfig.LoadDefaultConfig(ctx, opts...)
+}
+
+// NewStore creates a new DynamoDB store using the supplied client
+func NewStore(api API, cfg Config) *Store {
+	return &Store{
+		api:    api,
+		cfg:    cfg,
+		logger: logging.NewLogger(LoggerName),
+	}
+}
+
+// PreStart ensures the table exists before the service receives traffic,
+// creating it when configured to
+func (s *Store) PreStart(ctx context.Context) error {
+	if s.cfg.CreateTable {
+		return s.CreateTable(ctx)
+	}
+	_, err := s.api.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(s.cfg.Table)})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %q: %w", s.cfg.Table, err)
+	}
+	return nil
+}
+
+// Ping checks the table can be reached and is active
+func (s *Store) Ping(ctx context.Context) error {
+	out, err := s.api.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(s.cfg.Table)})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %q: %w", s.cfg.Table, err)
+	}
+	if status := out.Table.TableStatus; status != types.TableStatusActive && status != types.TableStatusUpdating {
+		return fmt.Errorf("table %q is %s", s.cfg.Table, status)
+	}
+	return nil
+}
+
+// Migrate creates the table and its indexes if they do not already exist
+func (s *Store) Migrate(ctx context.Context) error {
+	return s.CreateTable(ctx)
+}
+
+// CreateTable creates the table and its indexes if they do not already exist
+// and waits for the table to become active
+func (s *Store) CreateTable(ctx context.Context) error {
+	table := aws.String(s.cfg.Table)
+	_, err := s.api.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: table})
+	if err == nil {
+		return nil
+	}
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return fmt.Errorf("failed to describe table %q: %w", s.cfg.Table, err)
+	}
+
+	s.logger.Info("creating table", zap.String("table", s.cfg.Table))
+	_, err = s.api.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:   table,
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String(attrPK), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrSK), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrGSI1PK), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrGSI1SK), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrGSI2PK), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrGSI2SK), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: keySchema(attrPK, attrSK),
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName:  aws.String(IndexUser),
+				KeySchema:  keySchema(attrGSI1PK, attrGSI1SK),
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+			{
+				IndexName:  aws.String(IndexFeed),
+				KeySchema:  keySchema(attrGSI2PK, attrGSI2SK),
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+		},
+	})
+	if err != nil {
+		var inUse *types.ResourceInUseException
+		if !errors.As(err, &inUse) {
+			return fmt.Errorf("failed to create table %q: %w", s.cfg.Table, err)
+		}
+	}
+
+	// Wait for the table to become active
+	waiter := dynamodb.NewTableExistsWaiter(s.api, func(o *dynamodb.TableExistsWaiterOptions) {
+		o.MinDelay = time.Second
+		o.MaxDelay = 5 * time.Second
+	})
+	return waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: table}, 2*time.Minute)
+}
+
+// CreatePost stores a new post
+func (s *Store) CreatePost(ctx context.Context, post *kitchenv1.Post) error {
+	av, err := attributevalue.MarshalMap(s.toItem(post))
+	if err != nil {
+		return err
+	}
+	_, err = s.api.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.cfg.Table),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#pk)"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk": attrPK,
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return store.NewAlreadyExistsError(post.Id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to put post: %w", err)
+	}
+	return nil
+}
+
+// GetPost returns the post with the supplied id
+func (s *Store) GetPost(ctx context.Context, id string) (*kitchenv1.Post, error) {
+	out, err := s.api.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.cfg.Table),
+		Key:            key(id),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if len(out.Item) == 0 {
+		return nil, store.NewNotFoundError(id)
+	}
+	return fromAttributes(out.Item)
+}
+
+// BatchGetPosts returns the posts with the supplied ids that exist, reading
+// them in chunks of at most maxBatchGetKeys
+func (s *Store) BatchGetPosts(ctx context.Context, ids []string) (map[string]*kitchenv1.Post, error) {
+	posts := make(map[string]*kitchenv1.Post, len(ids))
+	for start := 0; start < len(ids); start += maxBatchGetKeys {
+		chunk := ids[start:min(start+maxBatchGetKeys, len(ids))]
+		keys := make([]map[string]types.AttributeValue, 0, len(chunk))
+		for _, id := range chunk {
+			keys = append(keys, key(id))
+		}
+		if err := s.batchGet(ctx, keys, posts); err != nil {
+			return nil, err
+		}
+	}
+	return posts, nil
+}
+
+// batchGet reads the keys into posts. DynamoDB may leave keys unprocessed when
+// throttled, these are retried with a backoff
+func (s *Store) batchGet(ctx context.Context, keys []map[string]types.AttributeValue, posts map[string]*kitchenv1.Post) error {
+	request := map[string]types.KeysAndAttributes{
+		s.cfg.Table: {Keys: keys, ConsistentRead: aws.Bool(true)},
+	}
+	backoff := 50 * time.Millisecond
+	for len(request) > 0 {
+		out, err := s.api.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+		if err != nil {
+			return fmt.Errorf("failed to batch get posts: %w", err)
+		}
+		for _, av := range out.Responses[s.cfg.Table] {
+			post, err := fromAttributes(av)
+			if err != nil {
+				return err
+			}
+			posts[post.Id] = post
+		}
+		request = out.UnprocessedKeys
+		if len(request) == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, time.Second)
+	}
+	return nil
+}
+
+// ListPosts lists a page of posts, newest first, using the user index when
+// filtering by user and the feed index otherwise. The feed is spread over
+// FeedShards partitions, which are queried in turn and merged
+func (s *Store) ListPosts(ctx context.Context, req *kitchenv1.ListPostsRequest) (*kitchenv1.ListPostsResponse, error) {
+	index, pkAttr, skAttr, partitions := IndexFeed, attrGSI2PK, attrGSI2SK, s.feedPartitions()
+	if req.UserId != "" {
+		index, pkAttr, skAttr, partitions = IndexUser, attrGSI1PK, attrGSI1SK, []string{userPrefix + req.UserId}
+	}
+
+	// Resume before the cursor, which is positioned at the last post returned
+	var before string
+	if req.PageToken != "" {
+		cursor, err := store.DecodePageToken(req.PageToken, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		before = sortKey(cursor.CreatedAt, cursor.ID)
+	}
+
+	// Read one more post than requested from every partition to find out if
+	// there is another page, then keep the newest across them
+	want := int(req.PageSize) + 1
+	var posts []*kitchenv1.Post
+	for _, partition := range partitions {
+		found, err := s.queryIndex(ctx, index, pkAttr, skAttr, partition, before, want)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, found...)
+	}
+	if len(partitions) > 1 {
+		slices.SortFunc(posts, func(a, b *kitchenv1.Post) int {
+			return strings.Compare(sortKey(b.CreatedAt.AsTime(), b.Id), sortKey(a.CreatedAt.AsTime(), a.Id))
+		})
+	}
+
+	resp := new(kitchenv1.ListPostsResponse)
+	if len(posts) > int(req.PageSize) {
+		posts = posts[:req.PageSize]
+		resp.NextPageToken = store.EncodePageToken(store.CursorFor(posts[len(posts)-1], req.UserId))
+	}
+	resp.Posts = posts
+	return resp, nil
+}
+
+// queryIndex reads up to limit posts of the index partition, newest first,
+// starting strictly before the sort key when it is set. A query stops early
+// when it reads 1MB, so keep going until we have enough posts or run out
+func (s *Store) queryIndex(ctx context.Context, index, pkAttr, skAttr, partition, before string, limit int) ([]*kitchenv1.Post, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.cfg.Table),
+		IndexName:              aws.String(index),
+		ScanIndexForward:       aws.Bool(false),
+		KeyConditionExpression: aws.String("#pk = :pk"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk": pkAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: partition},
+		},
+	}
+	if before != "" {
+		input.KeyConditionExpression = aws.String("#pk = :pk AND #sk < :before")
+		input.ExpressionAttributeNames["#sk"] = skAttr
+		input.ExpressionAttributeValues[":before"] = &types.AttributeValueMemberS{Value: before}
+	}
+
+	var posts []*kitchenv1.Post
+	for len(posts) < limit {
+		input.Limit = aws.Int32(int32(limit - len(posts)))
+		out, err := s.api.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query posts: %w", err)
+		}
+		for _, av := range out.Items {
+			post, err := fromAttributes(av)
+			if err != nil {
+				return nil, err
+			}
+			posts = append(posts, post)
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+	return posts, nil
+}
+
+// UpdatePost updates the mutable fields of the post when the versions match
+func (s *Store) UpdatePost(ctx context.Context, post *kitchenv1.Post) (*kitchenv1.Post, error) {
+	imageURLs, err := attributevalue.Marshal(post.ImageUrls)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.api.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.cfg.Table),
+		Key:                 key(post.Id),
+		UpdateExpression:    aws.String("SET #caption = :caption, #image_urls = :image_urls, #updated_at = :updated_at, #version = :next"),
+		ConditionExpression: aws.String("attribute_exists(#pk) AND #version = :version"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk":         attrPK,
+			"#caption":    "caption",
+			"#image_urls": "image_urls",
+			"#updated_at": "updated_at",
+			"#version":    "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":caption":    &types.AttributeValueMemberS{Value: post.Caption},
+			":image_urls": imageURLs,
+			":updated_at": number(post.UpdatedAt.AsTime().UnixNano()),
+			":version":    number(post.Version),
+			":next":       number(post.Version + 1),
+		},
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		return nil, conditionError(err, post.Id)
+	}
+	return fromAttributes(out.Attributes)
+}
+
+// DeletePost deletes the post when the versions match
+func (s *Store) DeletePost(ctx context.Context, id string, version int64) error {
+	_, err := s.api.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(s.cfg.Table),
+		Key:                 key(id),
+		ConditionExpression: aws.String("attribute_exists(#pk) AND #version = :version"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk":      attrPK,
+			"#version": "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": number(version),
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		return conditionError(err, id)
+	}
+	return nil
+}
+
+// conditionError converts a failed conditional write into a store error. The
+// write returns the old item on failure, which is empty when the post does not
+// exist
+func conditionError(err error, id string) error {
+	var failed *types.ConditionalCheckFailedException
+	if !errors.As(err, &failed) {
+		return err
+	}
+	if len(failed.Item) == 0 {
+		return store.NewNotFoundError(id)
+	}
+	return store.ErrVersionMismatch
+}
+
+// key returns the table key for the post id
+func key(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		attrPK: &types.AttributeValueMemberS{Value: postPrefix + id},
+		attrSK: &types.AttributeValueMemberS{Value: postSK},
+	}
+}
+
+// keySchema returns a hash and range key schema
+func keySchema(hash, rng string) []types.KeySchemaElement {
+	return []types.KeySchemaElement{
+		{AttributeName: aws.String(hash), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String(rng), KeyType: types.KeyTypeRange},
+	}
+}
+
+// sortKey returns the index sort key, which orders posts by creation time and
+// then id
+func sortKey(createdAt time.Time, id string) string {
+	return fmt.Sprintf("%020d#%s", createdAt.UnixNano(), id)
+}
+
+// number returns a number attribute value
+func number(n int64) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(n, 10)}
+}
+
+// feedPartitions returns every partition key of the feed index
+func (s *Store) feedPartitions() []string {
+	shards := max(s.cfg.FeedShards, 1)
+	partitions := make([]string, 0, shards)
+	for shard := range shards {
+		partitions = append(partitions, feedShardKey(shard, shards))
+	}
+	return partitions
+}
+
+// feedPartition returns the feed index partition key of the post
+func feedPartition(id string, shards int) string {
+	shards = max(shards, 1)
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return feedShardKey(int(h.Sum32()%uint32(shards)), shards)
+}
+
+// feedShardKey returns the partition key of the feed shard. An unsharded feed
+// keeps the original key, so existing tables keep working
+func feedShardKey(shard, shards int) string {
+	if shards == 1 {
+		return feedPK
+	}
+	return feedPK + "#" + strconv.Itoa(shard)
+}
+
+// toItem converts a post to its item
+func (s *Store) toItem(post *kitchenv1.Post) item {
+	createdAt := post.CreatedAt.AsTime()
+	return item{
+		PK:        postPrefix + post.Id,
+		SK:        postSK,
+		GSI1PK:    userPrefix + post.UserId,
+		GSI1SK:    sortKey(createdAt, post.Id),
+		GSI2PK:    feedPartition(post.Id, s.cfg.FeedShards),
+		GSI2SK:    sortKey(createdAt, post.Id),
+		ID:        post.Id,
+		UserID:    post.UserId,
+		Caption:   post.Caption,
+		ImageURLs: post.ImageUrls,
+		CreatedAt: createdAt.UnixNano(),
+		UpdatedAt: post.UpdatedAt.AsTime().UnixNano(),
+		Version:   post.Version,
+	}
+}
+
+// fromAttributes converts an item's attributes to a post
+func fromAttributes(av map[string]types.AttributeValue) (*kitchenv1.Post, error) {
+	var it item
+	if err := attributevalue.UnmarshalMap(av, &it); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal post: %w", err)
+	}
+	return &kitchenv1.Post{
+		Id:        it.ID,
+		Caption:   it.Caption,
+		UserId:    it.UserID,
+		ImageUrls: it.ImageURLs,
+		CreatedAt: timestamppb.New(time.Unix(0, it.CreatedAt)),
+		UpdatedAt: timestamppb.New(time.Unix(0, it.UpdatedAt)),
+		Version:   it.Version,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"kitchen/internal/store"
+	"kitchen/internal/store/storetest"
+)
+
+// endpointEnv names the dynamodb-local endpoint to run the suite against, for
+// example http://localhost:8000 with docker compose up
+const endpointEnv = "KITCHEN_TEST_DYNAMO_ENDPOINT"
+
+// tables is the sequence used to name the tables created in dynamodb-local
+var tables atomic.Int64
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func() store.Store {
+		return migrate(t, NewStore(newFakeAPI(), Config{Table: "kitchen", FeedShards: 1}))
+	})
+}
+
+func TestStoreShardedFeed(t *testing.T) {
+	storetest.Run(t, func() store.Store {
+		return migrate(t, NewStore(newFakeAPI(), Config{Table: "kitchen", FeedShards: 4}))
+	})
+}
+
+func TestStoreDynamoDBLocal(t *testing.T) {
+	endpoint := os.Getenv(endpointEnv)
+	if endpoint == "" {
+		t.Skipf("%s is not set", endpointEnv)
+	}
+	cfg := Config{
+		Endpoint:        endpoint,
+		Region:          "us-east-1",
+		AccessKeyID:     "local",
+		SecretAccessKey: "local",
+		FeedShards:      2,
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	storetest.Run(t, func() store.Store {
+		cfg := cfg
+		cfg.Table = fmt.Sprintf("kitchen-test-%d-%d", os.Getpid(), tables.Add(1))
+		return migrate(t, NewStore(client, cfg))
+	})
+}
+
+// migrate creates the store's table
+func migrate(t *testing.T, s *Store) *Store {
+	t.Helper()
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	return s
+}
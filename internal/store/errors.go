@@ -0,0 +1,61 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when a requested post does not exist. Stores
+	// return a *NotFoundError which matches ErrNotFound with errors.Is
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when a created post id is already taken.
+	// Stores return an *AlreadyExistsError which matches ErrAlreadyExists with
+	// errors.Is
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrVersionMismatch is returned when a write supplies a version that does
+	// not match the stored post
+	ErrVersionMismatch = errors.New("version mismatch")
+)
+
+// NotFoundError is returned when a requested resource does not exist
+type NotFoundError struct {
+	Resource string
+	ID       string
+}
+
+// NewNotFoundError creates a NotFoundError for the supplied post id
+func NewNotFoundError(id string) *NotFoundError {
+	return &NotFoundError{Resource: "post", ID: id}
+}
+
+// Error implements the error interface
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found", e.Resource, e.ID)
+}
+
+// Is reports whether the target is ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// AlreadyExistsError is returned when a created resource already exists
+type AlreadyExistsError struct {
+	Resource string
+	ID       string
+}
+
+// NewAlreadyExistsError creates an AlreadyExistsError for the supplied post id
+func NewAlreadyExistsError(id string) *AlreadyExistsError {
+	return &AlreadyExistsError{Resource: "post", ID: id}
+}
+
+// Error implements the error interface
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%s %q already exists", e.Resource, e.ID)
+}
+
+// Is reports whether the target is ErrAlreadyExists
+func (e *AlreadyExistsError) Is(target error) bool {
+	return target == ErrAlreadyExists
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"kitchen/internal/store"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ store.Store       = (*Store)(nil)
+	_ store.BatchGetter = (*Store)(nil)
+)
+
+// Store is an in-memory store.Store intended for local development and tests.
+// Posts are lost when the process exits
+type Store struct {
+	mu     sync.RWMutex
+	posts  map[string]*kitchenv1.Post
+	byUser map[string]map[string]struct{}
+}
+
+// NewStore creates a new, empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		posts:  make(map[string]*kitchenv1.Post),
+		byUser: make(map[string]map[string]struct{}),
+	}
+}
+
+// CreatePost stores a new post
+func (s *Store) CreatePost(ctx context.Context, post *kitchenv1.Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.posts[post.Id]; ok {
+		return store.NewAlreadyExistsError(post.Id)
+	}
+	post = clone(post)
+	s.posts[post.Id] = post
+	s.index(post)
+	return nil
+}
+
+// GetPost returns the post with the supplied id
+func (s *Store) GetPost(ctx context.Context, id string) (*kitchenv1.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	post, ok := s.posts[id]
+	if !ok {
+		return nil, store.NewNotFoundError(id)
+	}
+	return clone(post), nil
+}
+
+// BatchGetPosts returns the posts with the supplied ids that exist
+func (s *Store) BatchGetPosts(ctx context.Context, ids []string) (map[string]*kitchenv1.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	posts := make(map[string]*kitchenv1.Post, len(ids))
+	for _, id := range ids {
+		if post, ok := s.posts[id]; ok {
+			posts[id] = clone(post)
+		}
+	}
+	return posts, nil
+}
+
+// ListPosts lists a page of posts, newest first
+func (s *Store) ListPosts(ctx context.Context, req *kitchenv1.ListPostsRequest) (*kitchenv1.ListPostsResponse, error) {
+	var cursor *store.Cursor
+	if req.PageToken != "" {
+		c, err := store.DecodePageToken(req.PageToken, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Collect the candidate posts, using the user index when filtering
+	var candidates []*kitchenv1.Post
+	if req.UserId != "" {
+		for id := range s.byUser[req.UserId] {
+			candidates = append(candidates, s.posts[id])
+		}
+	} else {
+		for _, post := range s.posts {
+			candidates = append(candidates, post)
+		}
+	}
+
+	// Order them newest first and skip everything up to the cursor
+	sort.Slice(candidates, func(i, j int) bool {
+		return store.CursorFor(candidates[i], "").Precedes(candidates[j])
+	})
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(candidates), func(i int) bool {
+			return cursor.Precedes(candidates[i])
+		})
+	}
+
+	resp := new(kitchenv1.ListPostsResponse)
+	end := min(start+int(req.PageSize), len(candidates))
+	for _, post := range candidates[start:end] {
+		resp.Posts = append(resp.Posts, clone(post))
+	}
+	if end < len(candidates) && len(resp.Posts) > 0 {
+		resp.NextPageToken = store.EncodePageToken(store.CursorFor(resp.Posts[len(resp.Posts)-1], req.UserId))
+	}
+	return resp, nil
+}
+
+// UpdatePost replaces the stored post when the versions match
+func (s *Store) UpdatePost(ctx context.Context, post *kitchenv1.Post) (*kitchenv1.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.posts[post.Id]
+	if !ok {
+		return nil, store.NewNotFoundError(post.Id)
+	}
+	if current.Version != post.Version {
+		return nil, store.ErrVersionMismatch
+	}
+
+	// The identity and creation details of a post never change
+	updated := clone(post)
+	updated.UserId = current.UserId
+	updated.CreatedAt = current.CreatedAt
+	updated.Version = current.Version + 1
+	s.posts[post.Id] = updated
+	return clone(updated), nil
+}
+
+// DeletePost deletes the post when the versions match
+func (s *Store) DeletePost(ctx context.Context, id string, version int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.posts[id]
+	if !ok {
+		return store.NewNotFoundError(id)
+	}
+	if current.Version != version {
+		return store.ErrVersionMismatch
+	}
+	delete(s.posts, id)
+	if ids := s.byUser[current.UserId]; ids != nil {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(s.byUser, current.UserId)
+		}
+	}
+	return nil
+}
+
+// index adds the post to the secondary indexes, s.mu must be held
+func (s *Store) index(post *kitchenv1.Post) {
+	ids, ok := s.byUser[post.UserId]
+	if !ok {
+		ids = make(map[string]struct{})
+		s.byUser[post.UserId] = ids
+	}
+	ids[post.Id] = struct{}{}
+}
+
+// clone returns a deep copy of the post so callers cannot mutate stored state
+func clone(post *kitchenv1.Post) *kitchenv1.Post {
+	return proto.Clone(post).(*kitchenv1.Post)
+}
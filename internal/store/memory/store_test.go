@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"kitchen/internal/store"
+	"kitchen/internal/store/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func() store.Store {
+		return NewStore()
+	})
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+)
+
+// ErrInvalidPageToken is returned when a page token cannot be decoded
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// Cursor identifies the position of the last post returned in a page. Posts
+// are listed by (created_at, id) descending, so the next page starts with the
+// first post strictly before the cursor
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+	UserID    string    `json:"u,omitempty"`
+}
+
+// CursorFor returns the cursor positioned at the supplied post, scoped to the
+// user the listing was filtered by
+func CursorFor(post *kitchenv1.Post, userID string) Cursor {
+	return Cursor{
+		CreatedAt: post.GetCreatedAt().AsTime(),
+		ID:        post.GetId(),
+		UserID:    userID,
+	}
+}
+
+// Precedes returns if the cursor sorts strictly before the supplied post in
+// listing order, that is whether the post belongs on a page after the cursor
+func (c Cursor) Precedes(post *kitchenv1.Post) bool {
+	createdAt := post.GetCreatedAt().AsTime()
+	if createdAt.Equal(c.CreatedAt) {
+		return post.GetId() < c.ID
+	}
+	return createdAt.Before(c.CreatedAt)
+}
+
+// EncodePageToken encodes the cursor into an opaque page token
+func EncodePageToken(c Cursor) string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		// A Cursor only holds strings and a time, this cannot fail
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePageToken decodes an opaque page token for a listing filtered by the
+// supplied user. A token issued for a different filter is rejected
+func DecodePageToken(token string, userID string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	if c.ID == "" || c.UserID != userID {
+		return c, ErrInvalidPageToken
+	}
+	return c, nil
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"errors"
+	"kitchen/pkg/common/config"
+	"time"
+)
+
+func init() {
+	config.RegisterDefault("sqlite_path", "kitchen.db")
+	config.RegisterDefault("sqlite_busy_timeout", 5*time.Second)
+}
+
+// Config is the SQLite store config
+type Config struct {
+	// Path is the database file, ":memory:" keeps the database in memory
+	Path string `config:"sqlite_path"`
+	// BusyTimeout is how long a write waits for the database lock
+	BusyTimeout time.Duration `config:"sqlite_busy_timeout"`
+}
+
+// Validate validates this config
+func (c Config) Validate() error {
+	if c.Path == "" {
+		return errors.New("sqlite_path is required")
+	}
+	return nil
+}
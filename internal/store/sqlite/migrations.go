@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// migration is a versioned schema change. Migrations are applied in order and
+// must never be edited once released, add a new migration instead
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrations is the ordered set of schema migrations
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create posts",
+		sql: `
+CREATE TABLE posts (
+	id         TEXT    NOT NULL PRIMARY KEY,
+	user_id    TEXT    NOT NULL,
+	caption    TEXT    NOT NULL,
+	image_urls TEXT    NOT NULL DEFAULT '[]',
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	version    INTEGER NOT NULL
+);
+CREATE INDEX posts_user_id_created_at ON posts (user_id, created_at DESC, id DESC);
+CREATE INDEX posts_created_at ON posts (created_at DESC, id DESC);
+`,
+	},
+}
+
+// Migrate applies any pending migrations. Each migration runs in its own
+// transaction together with the bookkeeping row recording it
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER NOT NULL PRIMARY KEY,
+	name       TEXT    NOT NULL,
+	applied_at INTEGER NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	current, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		s.logger.Info("applying migration", zap.Int("version", m.version), zap.String("name", m.name))
+		if err := s.apply(ctx, m); err != nil {
+			return fmt.Errorf("migration %d %q failed: %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the version of the last applied migration
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	var version sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// apply applies a single migration
+func (s *Store) apply(ctx context.Context, m migration) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, time.Now().UnixNano())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
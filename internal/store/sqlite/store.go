@@ -0,0 +1,289 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"kitchen/internal/store"
+	"kitchen/pkg/common/logging"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// LoggerName the logger name to use for the store
+const LoggerName = "store.sqlite"
+
+// postColumns are the columns scanned by scanPost, in order
+const postColumns = `id, user_id, caption, image_urls, created_at, updated_at, version`
+
+var (
+	_ store.Store       = (*Store)(nil)
+	_ store.BatchGetter = (*Store)(nil)
+	_ store.Migrator    = (*Store)(nil)
+)
+
+// Store is a store.Store backed by an embedded SQLite database
+type Store struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// Open opens the SQLite database described by the config. The schema is not
+// migrated until Migrate or PreStart is called
+func Open(cfg Config) (*Store, error) {
+	db, err := sql.Open("sqlite", dsn(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// Every connection to an in-memory database gets its own database, so
+	// limit the pool to a single connection
+	if cfg.Path == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
+	return &Store{
+		db:     db,
+		logger: logging.NewLogger(LoggerName),
+	}, nil
+}
+
+// dsn builds the driver data source name. Transactions take the write lock
+// up front so read-then-write transactions cannot deadlock
+func dsn(cfg Config) string {
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	params.Add("_pragma", "foreign_keys(1)")
+	if cfg.Path != ":memory:" {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+	params.Add("_txlock", "immediate")
+	return "file:" + cfg.Path + "?" + params.Encode()
+}
+
+// PreStart migrates the schema before the service receives traffic
+func (s *Store) PreStart(ctx context.Context) error {
+	return s.Migrate(ctx)
+}
+
+// Ping checks the database can be reached
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Shutdown closes the database
+func (s *Store) Shutdown(ctx context.Context) error {
+	s.logger.Info("closing database")
+	return s.db.Close()
+}
+
+// CreatePost stores a new post
+func (s *Store) CreatePost(ctx context.Context, post *kitchenv1.Post) error {
+	imageURLs, err := marshalImageURLs(post.ImageUrls)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO posts (`+postColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		post.Id, post.UserId, post.Caption, imageURLs,
+		unixNano(post.CreatedAt), unixNano(post.UpdatedAt), post.Version)
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+		return store.NewAlreadyExistsError(post.Id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert post: %w", err)
+	}
+	return nil
+}
+
+// GetPost returns the post with the supplied id
+func (s *Store) GetPost(ctx context.Context, id string) (*kitchenv1.Post, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+postColumns+` FROM posts WHERE id = ?`, id)
+	post, err := scanPost(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.NewNotFoundError(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	return post, nil
+}
+
+// BatchGetPosts returns the posts with the supplied ids that exist
+func (s *Store) BatchGetPosts(ctx context.Context, ids []string) (map[string]*kitchenv1.Post, error) {
+	posts := make(map[string]*kitchenv1.Post, len(ids))
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.Repeat(`?, `, len(ids)-1) + `?`
+	rows, err := s.db.QueryContext(ctx, `SELECT `+postColumns+` FROM posts WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts[post.Id] = post
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+	return posts, nil
+}
+
+// ListPosts lists a page of posts, newest first
+func (s *Store) ListPosts(ctx context.Context, req *kitchenv1.ListPostsRequest) (*kitchenv1.ListPostsResponse, error) {
+	var where []string
+	var args []any
+	if req.UserId != "" {
+		where = append(where, `user_id = ?`)
+		args = append(args, req.UserId)
+	}
+	if req.PageToken != "" {
+		cursor, err := store.DecodePageToken(req.PageToken, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		createdAt := cursor.CreatedAt.UnixNano()
+		where = append(where, `(created_at < ? OR (created_at = ? AND id < ?))`)
+		args = append(args, createdAt, createdAt, cursor.ID)
+	}
+	query := `SELECT ` + postColumns + ` FROM posts`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	// Read one more post than requested to find out if there is another page
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, req.PageSize+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+	defer rows.Close()
+	var posts []*kitchenv1.Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	resp := new(kitchenv1.ListPostsResponse)
+	if len(posts) > int(req.PageSize) {
+		posts = posts[:req.PageSize]
+		resp.NextPageToken = store.EncodePageToken(store.CursorFor(posts[len(posts)-1], req.UserId))
+	}
+	resp.Posts = posts
+	return resp, nil
+}
+
+// UpdatePost updates the mutable fields of the post when the versions match
+func (s *Store) UpdatePost(ctx context.Context, post *kitchenv1.Post) (*kitchenv1.Post, error) {
+	imageURLs, err := marshalImageURLs(post.ImageUrls)
+	if err != nil {
+		return nil, err
+	}
+	row := s.db.QueryRowContext(ctx, `
+UPDATE posts SET caption = ?, image_urls = ?, updated_at = ?, version = version + 1
+WHERE id = ? AND version = ?
+RETURNING `+postColumns,
+		post.Caption, imageURLs, unixNano(post.UpdatedAt), post.Id, post.Version)
+	updated, err := scanPost(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, s.conflict(ctx, post.Id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update post: %w", err)
+	}
+	return updated, nil
+}
+
+// DeletePost deletes the post when the versions match
+func (s *Store) DeletePost(ctx context.Context, id string, version int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM posts WHERE id = ? AND version = ?`, id, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+	if n == 0 {
+		return s.conflict(ctx, id)
+	}
+	return nil
+}
+
+// conflict works out why a conditional write matched no rows, either the post
+// does not exist or its version has moved on
+func (s *Store) conflict(ctx context.Context, id string) error {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = ?)`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check post: %w", err)
+	}
+	if !exists {
+		return store.NewNotFoundError(id)
+	}
+	return store.ErrVersionMismatch
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanPost scans the postColumns into a post
+func scanPost(row scanner) (*kitchenv1.Post, error) {
+	var post kitchenv1.Post
+	var imageURLs string
+	var createdAt, updatedAt int64
+	err := row.Scan(&post.Id, &post.UserId, &post.Caption, &imageURLs, &createdAt, &updatedAt, &post.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(imageURLs), &post.ImageUrls); err != nil {
+		return nil, fmt.Errorf("invalid image_urls: %w", err)
+	}
+	post.CreatedAt = timestamppb.New(time.Unix(0, createdAt))
+	post.UpdatedAt = timestamppb.New(time.Unix(0, updatedAt))
+	return &post, nil
+}
+
+// unixNano returns the timestamp as nanoseconds since the epoch, the unit the
+// timestamp columns are stored in
+func unixNano(ts *timestamppb.Timestamp) int64 {
+	return ts.AsTime().UnixNano()
+}
+
+// marshalImageURLs encodes the image urls as a JSON array
+func marshalImageURLs(urls []string) (string, error) {
+	if urls == nil {
+		urls = []string{}
+	}
+	b, err := json.Marshal(urls)
+	return string(b), err
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kitchen/internal/store"
+	"kitchen/internal/store/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func() store.Store {
+		return open(t, Config{Path: filepath.Join(t.TempDir(), "kitchen.db"), BusyTimeout: 5 * time.Second})
+	})
+}
+
+func TestStoreInMemory(t *testing.T) {
+	storetest.Run(t, func() store.Store {
+		return open(t, Config{Path: ":memory:", BusyTimeout: 5 * time.Second})
+	})
+}
+
+// open opens and migrates a store, closing it when the test completes
+func open(t *testing.T, cfg Config) *Store {
+	t.Helper()
+	s, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	return s
+}
@@ -6,7 +6,39 @@ import (
 	kitchenv1 "kitchen/proto/gen/kitchen/v1"
 )
 
+// Store persists posts. Implementations must be safe for concurrent use
 type Store interface {
-	CreatePost(ctx context.Context, req *kitchenv1.CreatePostRequest) (*kitchenv1.CreatePostResponse, error)
+	// CreatePost stores a new post as supplied, the caller assigns its id,
+	// version and timestamps. An *AlreadyExistsError is returned if the id is
+	// taken
+	CreatePost(ctx context.Context, post *kitchenv1.Post) error
+	// GetPost returns the post with the supplied id, or a *NotFoundError
 	GetPost(ctx context.Context, id string) (*kitchenv1.Post, error)
+	// ListPosts lists posts ordered by created_at descending, breaking ties by
+	// id descending. The request page_size has already been normalized by the
+	// caller and the page_token is a token produced by EncodePageToken
+	ListPosts(ctx context.Context, req *kitchenv1.ListPostsRequest) (*kitchenv1.ListPostsResponse, error)
+	// UpdatePost replaces the stored post if its version matches the version
+	// of the supplied post, otherwise ErrVersionMismatch is returned. On
+	// success the version is incremented, updated_at is taken from the
+	// supplied post and the stored post is returned
+	UpdatePost(ctx context.Context, post *kitchenv1.Post) (*kitchenv1.Post, error)
+	// DeletePost deletes the post if its version matches the supplied version,
+	// otherwise ErrVersionMismatch is returned
+	DeletePost(ctx context.Context, id string, version int64) error
+}
+
+// BatchGetter is implemented by stores that can read several posts in a single
+// round trip. Stores without it are read with concurrent GetPost calls
+type BatchGetter interface {
+	// BatchGetPosts returns the posts with the supplied ids keyed by id. Ids
+	// that do not exist are absent from the result rather than an error
+	BatchGetPosts(ctx context.Context, ids []string) (map[string]*kitchenv1.Post, error)
+}
+
+// Migrator is implemented by stores whose schema must be created or upgraded
+// before use
+type Migrator interface {
+	// Migrate brings the schema up to date, it is safe to call repeatedly
+	Migrate(ctx context.Context) error
 }
@@ -0,0 +1,398 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kitchen/internal/store"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ids is the sequence used to assign post ids, stores never generate them
+var ids atomic.Int64
+
+// Factory creates a new, empty store for a single test
+type Factory func() store.Store
+
+// Run runs the store conformance suite against stores created by the supplied
+// factory. Every backend's tests should call it so all backends behave the
+// same
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, store.Store)
+	}{
+		{"CreateGetRoundTrip", testCreateGetRoundTrip},
+		{"GetNotFound", testGetNotFound},
+		{"Timestamps", testTimestamps},
+		{"CreateAlreadyExists", testCreateAlreadyExists},
+		{"ConcurrentCreates", testConcurrentCreates},
+		{"BatchGetPosts", testBatchGetPosts},
+		{"ListPosts", testListPosts},
+		{"ListPostsByUser", testListPostsByUser},
+		{"ListPostsInvalidToken", testListPostsInvalidToken},
+		{"UpdatePost", testUpdatePost},
+		{"UpdatePostStale", testUpdatePostStale},
+		{"UpdatePostNotFound", testUpdatePostNotFound},
+		{"DeletePost", testDeletePost},
+		{"DeletePostStale", testDeletePostStale},
+		{"DeletePostNotFound", testDeletePostNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory())
+		})
+	}
+}
+
+func testCreateGetRoundTrip(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	id := create(t, s, "user-1", "hello")
+	post, err := s.GetPost(ctx, id)
+	if err != nil {
+		t.Fatalf("GetPost(%q) failed: %v", id, err)
+	}
+	if post.Id != id {
+		t.Errorf("id = %q, want %q", post.Id, id)
+	}
+	if post.UserId != "user-1" {
+		t.Errorf("user_id = %q, want %q", post.UserId, "user-1")
+	}
+	if post.Caption != "hello" {
+		t.Errorf("caption = %q, want %q", post.Caption, "hello")
+	}
+	if post.Version != 1 {
+		t.Errorf("version = %d, want 1", post.Version)
+	}
+}
+
+func testGetNotFound(t *testing.T, s store.Store) {
+	_, err := s.GetPost(context.Background(), "does-not-exist")
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("GetPost error = %v, want store.ErrNotFound", err)
+	}
+	var notFound *store.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("GetPost error = %T, want *store.NotFoundError", err)
+	}
+	if notFound.ID != "does-not-exist" {
+		t.Errorf("NotFoundError.ID = %q, want %q", notFound.ID, "does-not-exist")
+	}
+}
+
+func testTimestamps(t *testing.T, s store.Store) {
+	// Stores must keep the timestamps they are given to the nanosecond
+	post := newPost("user-1", "hello")
+	post.CreatedAt = timestamppb.New(time.Date(2024, 5, 17, 9, 30, 15, 123456789, time.UTC))
+	post.UpdatedAt = timestamppb.New(time.Date(2024, 5, 18, 10, 0, 0, 987654321, time.UTC))
+	if err := s.CreatePost(context.Background(), post); err != nil {
+		t.Fatalf("CreatePost failed: %v", err)
+	}
+
+	stored := get(t, s, post.Id)
+	if !stored.CreatedAt.AsTime().Equal(post.CreatedAt.AsTime()) {
+		t.Errorf("created_at = %v, want %v", stored.CreatedAt.AsTime(), post.CreatedAt.AsTime())
+	}
+	if !stored.UpdatedAt.AsTime().Equal(post.UpdatedAt.AsTime()) {
+		t.Errorf("updated_at = %v, want %v", stored.UpdatedAt.AsTime(), post.UpdatedAt.AsTime())
+	}
+}
+
+func testCreateAlreadyExists(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	id := create(t, s, "user-1", "original")
+
+	duplicate := newPost("user-2", "duplicate")
+	duplicate.Id = id
+	err := s.CreatePost(ctx, duplicate)
+	if !errors.Is(err, store.ErrAlreadyExists) {
+		t.Fatalf("CreatePost error = %v, want store.ErrAlreadyExists", err)
+	}
+	var alreadyExists *store.AlreadyExistsError
+	if !errors.As(err, &alreadyExists) {
+		t.Fatalf("CreatePost error = %T, want *store.AlreadyExistsError", err)
+	}
+	if alreadyExists.ID != id {
+		t.Errorf("AlreadyExistsError.ID = %q, want %q", alreadyExists.ID, id)
+	}
+	if post := get(t, s, id); post.Caption != "original" {
+		t.Errorf("caption = %q, want %q", post.Caption, "original")
+	}
+}
+
+func testConcurrentCreates(t *testing.T, s store.Store) {
+	const n = 50
+	ctx := context.Background()
+	posts := make([]*kitchenv1.Post, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		posts[i] = newPost("user-1", fmt.Sprintf("post %d", i))
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.CreatePost(ctx, posts[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("CreatePost %d failed: %v", i, err)
+		}
+	}
+	for i, post := range posts {
+		if stored := get(t, s, post.Id); stored.Caption != fmt.Sprintf("post %d", i) {
+			t.Errorf("post %q caption = %q, want %q", post.Id, stored.Caption, fmt.Sprintf("post %d", i))
+		}
+	}
+	if listed := list(t, s, "user-1", 20); len(listed) != n {
+		t.Errorf("listed %d posts, want %d", len(listed), n)
+	}
+}
+
+func testBatchGetPosts(t *testing.T, s store.Store) {
+	batchGetter, ok := s.(store.BatchGetter)
+	if !ok {
+		t.Skip("store does not implement store.BatchGetter")
+	}
+	ids := []string{
+		create(t, s, "user-1", "first"),
+		"does-not-exist",
+		create(t, s, "user-2", "second"),
+	}
+	posts, err := batchGetter.BatchGetPosts(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("BatchGetPosts failed: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("BatchGetPosts returned %d posts, want 2", len(posts))
+	}
+	for _, id := range []string{ids[0], ids[2]} {
+		post, ok := posts[id]
+		if !ok {
+			t.Errorf("BatchGetPosts did not return post %q", id)
+			continue
+		}
+		if post.Id != id {
+			t.Errorf("post keyed %q has id %q", id, post.Id)
+		}
+	}
+	if _, ok := posts["does-not-exist"]; ok {
+		t.Errorf("BatchGetPosts returned a post for an unknown id")
+	}
+}
+
+func testListPosts(t *testing.T, s store.Store) {
+	const n = 7
+	created := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		created[create(t, s, fmt.Sprintf("user-%d", i%3), "hello")] = true
+	}
+
+	posts := list(t, s, "", 3)
+	if len(posts) != n {
+		t.Fatalf("listed %d posts, want %d", len(posts), n)
+	}
+	for _, post := range posts {
+		if !created[post.Id] {
+			t.Errorf("listed unexpected post %q", post.Id)
+		}
+		delete(created, post.Id)
+	}
+	assertNewestFirst(t, posts)
+}
+
+func testListPostsByUser(t *testing.T, s store.Store) {
+	for i := 0; i < 5; i++ {
+		create(t, s, "user-1", "mine")
+		create(t, s, "user-2", "theirs")
+	}
+
+	posts := list(t, s, "user-1", 2)
+	if len(posts) != 5 {
+		t.Fatalf("listed %d posts, want 5", len(posts))
+	}
+	for _, post := range posts {
+		if post.UserId != "user-1" {
+			t.Errorf("listed post %q for user %q, want user-1", post.Id, post.UserId)
+		}
+	}
+	assertNewestFirst(t, posts)
+
+	if posts := list(t, s, "nobody", 2); len(posts) != 0 {
+		t.Errorf("listed %d posts for unknown user, want 0", len(posts))
+	}
+}
+
+func testListPostsInvalidToken(t *testing.T, s store.Store) {
+	_, err := s.ListPosts(context.Background(), &kitchenv1.ListPostsRequest{
+		PageSize:  10,
+		PageToken: "not a token",
+	})
+	if !errors.Is(err, store.ErrInvalidPageToken) {
+		t.Fatalf("ListPosts error = %v, want store.ErrInvalidPageToken", err)
+	}
+}
+
+func testUpdatePost(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	post := get(t, s, create(t, s, "user-1", "before"))
+	post.Caption = "after"
+	post.ImageUrls = []string{"https://example.com/1.png"}
+	post.UpdatedAt = timestamppb.New(post.UpdatedAt.AsTime().Add(time.Minute))
+
+	updated, err := s.UpdatePost(ctx, post)
+	if err != nil {
+		t.Fatalf("UpdatePost failed: %v", err)
+	}
+	if updated.Version != post.Version+1 {
+		t.Errorf("version = %d, want %d", updated.Version, post.Version+1)
+	}
+	if !updated.UpdatedAt.AsTime().Equal(post.UpdatedAt.AsTime()) {
+		t.Errorf("updated_at = %v, want %v", updated.UpdatedAt.AsTime(), post.UpdatedAt.AsTime())
+	}
+
+	stored := get(t, s, post.Id)
+	if stored.Caption != "after" {
+		t.Errorf("caption = %q, want %q", stored.Caption, "after")
+	}
+	if len(stored.ImageUrls) != 1 || stored.ImageUrls[0] != "https://example.com/1.png" {
+		t.Errorf("image_urls = %v, want [https://example.com/1.png]", stored.ImageUrls)
+	}
+	if stored.Version != updated.Version {
+		t.Errorf("stored version = %d, want %d", stored.Version, updated.Version)
+	}
+	if !stored.CreatedAt.AsTime().Equal(post.CreatedAt.AsTime()) {
+		t.Errorf("created_at changed from %v to %v", post.CreatedAt.AsTime(), stored.CreatedAt.AsTime())
+	}
+}
+
+func testUpdatePostStale(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	post := get(t, s, create(t, s, "user-1", "before"))
+	if _, err := s.UpdatePost(ctx, post); err != nil {
+		t.Fatalf("UpdatePost failed: %v", err)
+	}
+
+	// Writing with the version we originally read must now conflict
+	post.Caption = "stale"
+	if _, err := s.UpdatePost(ctx, post); !errors.Is(err, store.ErrVersionMismatch) {
+		t.Fatalf("UpdatePost error = %v, want store.ErrVersionMismatch", err)
+	}
+	if stored := get(t, s, post.Id); stored.Caption != "before" {
+		t.Errorf("caption = %q, want %q", stored.Caption, "before")
+	}
+}
+
+func testUpdatePostNotFound(t *testing.T, s store.Store) {
+	_, err := s.UpdatePost(context.Background(), &kitchenv1.Post{Id: "does-not-exist", Version: 1})
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("UpdatePost error = %v, want store.ErrNotFound", err)
+	}
+}
+
+func testDeletePost(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	post := get(t, s, create(t, s, "user-1", "hello"))
+	if err := s.DeletePost(ctx, post.Id, post.Version); err != nil {
+		t.Fatalf("DeletePost failed: %v", err)
+	}
+	if _, err := s.GetPost(ctx, post.Id); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("GetPost after delete error = %v, want store.ErrNotFound", err)
+	}
+	if posts := list(t, s, "user-1", 10); len(posts) != 0 {
+		t.Errorf("listed %d posts after delete, want 0", len(posts))
+	}
+}
+
+func testDeletePostStale(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	post := get(t, s, create(t, s, "user-1", "hello"))
+	if err := s.DeletePost(ctx, post.Id, post.Version+1); !errors.Is(err, store.ErrVersionMismatch) {
+		t.Fatalf("DeletePost error = %v, want store.ErrVersionMismatch", err)
+	}
+	get(t, s, post.Id)
+}
+
+func testDeletePostNotFound(t *testing.T, s store.Store) {
+	err := s.DeletePost(context.Background(), "does-not-exist", 1)
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("DeletePost error = %v, want store.ErrNotFound", err)
+	}
+}
+
+// newPost returns a new post as the manager would create it
+func newPost(userID, caption string) *kitchenv1.Post {
+	now := timestamppb.Now()
+	return &kitchenv1.Post{
+		Id:        fmt.Sprintf("post-%08d", ids.Add(1)),
+		UserId:    userID,
+		Caption:   caption,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+}
+
+// create creates a post, failing the test on error
+func create(t *testing.T, s store.Store, userID, caption string) string {
+	t.Helper()
+	post := newPost(userID, caption)
+	if err := s.CreatePost(context.Background(), post); err != nil {
+		t.Fatalf("CreatePost failed: %v", err)
+	}
+	return post.Id
+}
+
+// get gets a post, failing the test on error
+func get(t *testing.T, s store.Store, id string) *kitchenv1.Post {
+	t.Helper()
+	post, err := s.GetPost(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetPost(%q) failed: %v", id, err)
+	}
+	return post
+}
+
+// list pages through every post for the user, failing the test on error
+func list(t *testing.T, s store.Store, userID string, pageSize int32) []*kitchenv1.Post {
+	t.Helper()
+	var posts []*kitchenv1.Post
+	var token string
+	for {
+		resp, err := s.ListPosts(context.Background(), &kitchenv1.ListPostsRequest{
+			UserId:    userID,
+			PageSize:  pageSize,
+			PageToken: token,
+		})
+		if err != nil {
+			t.Fatalf("ListPosts failed: %v", err)
+		}
+		if len(resp.Posts) > int(pageSize) {
+			t.Fatalf("ListPosts returned %d posts, want at most %d", len(resp.Posts), pageSize)
+		}
+		posts = append(posts, resp.Posts...)
+		if resp.NextPageToken == "" {
+			return posts
+		}
+		token = resp.NextPageToken
+	}
+}
+
+// assertNewestFirst asserts the posts are in listing order
+func assertNewestFirst(t *testing.T, posts []*kitchenv1.Post) {
+	t.Helper()
+	for i := 1; i < len(posts); i++ {
+		if !store.CursorFor(posts[i-1], "").Precedes(posts[i]) {
+			t.Errorf("post %q listed before %q, want newest first", posts[i-1].Id, posts[i].Id)
+		}
+	}
+}
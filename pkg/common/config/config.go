@@ -70,3 +70,9 @@ func Load(flags *pflag.FlagSet) (Config, error) {
 func LoadInto(flags *pflag.FlagSet, to interface{}) error {
 	return stdLoader.LoadInto(flags, to)
 }
+
+// LoadedConfig returns the config last loaded by the standard Loader, or nil if
+// none has been loaded
+func LoadedConfig() Config {
+	return stdLoader.LoadedConfig()
+}
@@ -150,6 +150,9 @@ func (l *loader) LoadInto(flags *pflag.FlagSet, to interface{}) error {
 
 // LoadedConfig returns the loaded config
 func (l *loader) LoadedConfig() Config {
+	if l.config == nil {
+		return nil
+	}
 	return l.config
 }
 
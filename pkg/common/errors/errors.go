@@ -0,0 +1,225 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Kind classifies a domain error. Transports map each kind to their own status
+// codes
+type Kind int
+
+const (
+	// KindUnknown is any error that is not a domain error
+	KindUnknown Kind = iota
+	// KindNotFound indicates a requested resource does not exist
+	KindNotFound
+	// KindAlreadyExists indicates a resource being created already exists
+	KindAlreadyExists
+	// KindInvalidArgument indicates the caller supplied invalid input
+	KindInvalidArgument
+	// KindPermissionDenied indicates the caller may not perform the operation
+	KindPermissionDenied
+	// KindConflict indicates the operation conflicts with the current state of
+	// a resource, for example a stale write
+	KindConflict
+	// KindUnauthenticated indicates the caller could not be identified
+	KindUnauthenticated
+	// KindResourceExhausted indicates the caller ran out of quota, for example
+	// by exceeding a rate limit
+	KindResourceExhausted
+	// KindFailedPrecondition indicates the system is not in the state the
+	// operation requires, and retrying it unchanged will not help
+	KindFailedPrecondition
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindAlreadyExists:
+		return "already_exists"
+	case KindInvalidArgument:
+		return "invalid_argument"
+	case KindPermissionDenied:
+		return "permission_denied"
+	case KindConflict:
+		return "conflict"
+	case KindUnauthenticated:
+		return "unauthenticated"
+	case KindResourceExhausted:
+		return "resource_exhausted"
+	case KindFailedPrecondition:
+		return "failed_precondition"
+	default:
+		return "unknown"
+	}
+}
+
+// FieldViolation describes a single invalid request field
+type FieldViolation struct {
+	// Field is the path to the field, for example post.caption
+	Field string
+	// Description explains why the field is invalid
+	Description string
+}
+
+// Error is a domain error. Its Message, Reason, Metadata and Violations are
+// safe to return to callers, its cause is internal and is only logged
+type Error struct {
+	Kind Kind
+	// Reason is a short UPPER_SNAKE_CASE identifier for the error
+	Reason     string
+	Message    string
+	Metadata   map[string]string
+	Violations []FieldViolation
+	// RetryDelay is how long the caller should wait before retrying, zero
+	// when unknown
+	RetryDelay time.Duration
+	cause      error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the internal cause of the error
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Cause returns the internal cause of the error
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+// WithCause returns a copy of the error with the supplied internal cause
+func (e *Error) WithCause(cause error) *Error {
+	c := *e
+	c.cause = cause
+	return &c
+}
+
+// WithReason returns a copy of the error with the supplied reason
+func (e *Error) WithReason(reason string) *Error {
+	c := *e
+	c.Reason = reason
+	return &c
+}
+
+// WithRetryDelay returns a copy of the error with the supplied retry delay
+func (e *Error) WithRetryDelay(delay time.Duration) *Error {
+	c := *e
+	c.RetryDelay = delay
+	return &c
+}
+
+// NotFound creates an error indicating the resource with the supplied id does
+// not exist
+func NotFound(resource, id string) *Error {
+	return &Error{
+		Kind:     KindNotFound,
+		Reason:   "NOT_FOUND",
+		Message:  fmt.Sprintf("%s %q not found", resource, id),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// AlreadyExists creates an error indicating the resource with the supplied id
+// already exists
+func AlreadyExists(resource, id string) *Error {
+	return &Error{
+		Kind:     KindAlreadyExists,
+		Reason:   "ALREADY_EXISTS",
+		Message:  fmt.Sprintf("%s %q already exists", resource, id),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// InvalidArgument creates an error indicating the request was invalid, with
+// optional per-field violations
+func InvalidArgument(message string, violations ...FieldViolation) *Error {
+	return &Error{
+		Kind:       KindInvalidArgument,
+		Reason:     "INVALID_ARGUMENT",
+		Message:    message,
+		Violations: violations,
+	}
+}
+
+// PermissionDenied creates an error indicating the caller may not perform the
+// operation
+func PermissionDenied(message string) *Error {
+	return &Error{
+		Kind:    KindPermissionDenied,
+		Reason:  "PERMISSION_DENIED",
+		Message: message,
+	}
+}
+
+// Conflict creates an error indicating the operation conflicts with the
+// current state of a resource
+func Conflict(message string) *Error {
+	return &Error{
+		Kind:    KindConflict,
+		Reason:  "CONFLICT",
+		Message: message,
+	}
+}
+
+// Unauthenticated creates an error indicating the caller could not be
+// identified
+func Unauthenticated(message string) *Error {
+	return &Error{
+		Kind:    KindUnauthenticated,
+		Reason:  "UNAUTHENTICATED",
+		Message: message,
+	}
+}
+
+// ResourceExhausted creates an error indicating the caller ran out of quota
+func ResourceExhausted(message string) *Error {
+	return &Error{
+		Kind:    KindResourceExhausted,
+		Reason:  "RESOURCE_EXHAUSTED",
+		Message: message,
+	}
+}
+
+// FailedPrecondition creates an error indicating the system is not in the
+// state the operation requires
+func FailedPrecondition(message string) *Error {
+	return &Error{
+		Kind:    KindFailedPrecondition,
+		Reason:  "FAILED_PRECONDITION",
+		Message: message,
+	}
+}
+
+// As finds the first domain error in the error's chain
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of the first domain error in the error's chain, or
+// KindUnknown if there is none
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return KindUnknown
+}
+
+// IsNotFound returns if the error is a not found domain error
+func IsNotFound(err error) bool {
+	return KindOf(err) == KindNotFound
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"kitchen/pkg/common/config"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Configure configures the global meter provider from the config. Metrics are
+// always collected for the returned Prometheus scrape handler and are also
+// pushed over OTLP/gRPC to MetricsAddress when it is set, as host:port for a
+// plaintext collector or an http:// or https:// URL. Go runtime metrics are
+// included when ExportRuntimeMetrics is set. The provider must be shut down
+// to flush the last push
+func Configure(ctx context.Context, cfg config.Base) (*sdkmetric.MeterProvider, http.Handler, error) {
+
+	// Collect into a dedicated registry so only our metrics are scraped
+	registry := prometheus.NewRegistry()
+	scrape, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		semconv.DeploymentEnvironment(cfg.Env.String()),
+	))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create metrics resource: %w", err)
+	}
+	opts := []sdkmetric.Option{sdkmetric.WithReader(scrape), sdkmetric.WithResource(res)}
+
+	// Push to the collector as well when one is configured
+	if cfg.MetricsAddress != "" {
+		exporterOpts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(cfg.MetricsAddress), otlpmetricgrpc.WithInsecure()}
+		if strings.Contains(cfg.MetricsAddress, "://") {
+			exporterOpts = []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpointURL(cfg.MetricsAddress)}
+		}
+		exporter, err := otlpmetricgrpc.New(ctx, exporterOpts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create metrics exporter: %w", err)
+		}
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+	}
+
+	provider := sdkmetric.NewMeterProvider(opts...)
+	otel.SetMeterProvider(provider)
+	if cfg.ExportRuntimeMetrics {
+		if err := runtime.Start(runtime.WithMeterProvider(provider)); err != nil {
+			return nil, nil, fmt.Errorf("failed to start runtime metrics: %w", err)
+		}
+	}
+	return provider, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kitchen/pkg/common/config"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+	"kitchen/proto/gen/kitchen/v1/kitchenv1connect"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/otelconnect"
+	"go.opentelemetry.io/otel"
+)
+
+// kitchenService answers GetPost with an empty post
+type kitchenService struct {
+	kitchenv1connect.UnimplementedKitchenServiceHandler
+}
+
+func (kitchenService) GetPost(_ context.Context, req *connect.Request[kitchenv1.GetPostRequest]) (*connect.Response[kitchenv1.GetPostResponse], error) {
+	return connect.NewResponse(&kitchenv1.GetPostResponse{Post: &kitchenv1.Post{Id: req.Msg.Id}}), nil
+}
+
+func TestScrapeRPCMetrics(t *testing.T) {
+	previous := otel.GetMeterProvider()
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	provider, scrape, err := Configure(context.Background(), config.Base{Env: config.Test, ServiceName: "kitchen"})
+	if err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	// Serve a call instrumented as the server instruments them
+	interceptor, err := otelconnect.NewInterceptor(otelconnect.WithMeterProvider(provider), otelconnect.WithoutServerPeerAttributes())
+	if err != nil {
+		t.Fatalf("failed to create interceptor: %v", err)
+	}
+	path, handler := kitchenv1connect.NewKitchenServiceHandler(kitchenService{}, connect.WithInterceptors(interceptor))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client := kitchenv1connect.NewKitchenServiceClient(server.Client(), server.URL)
+	if _, err := client.GetPost(context.Background(), connect.NewRequest(&kitchenv1.GetPostRequest{Id: "1"})); err != nil {
+		t.Fatalf("GetPost failed: %v", err)
+	}
+
+	// The RPC metrics of the call are scraped
+	recorder := httptest.NewRecorder()
+	scrape.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	body, _ := io.ReadAll(recorder.Body)
+	for _, want := range []string{
+		"rpc_server_duration_milliseconds_count{",
+		"rpc_server_request_size_bytes_count{",
+		`rpc_method="GetPost"`,
+		`rpc_service="kitchen.v1.KitchenService"`,
+		`service_name="kitchen"`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("scraped metrics do not contain %s:\n%s", want, body)
+		}
+	}
+}
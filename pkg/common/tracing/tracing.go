@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"kitchen/pkg/common/config"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Configure configures the global tracer provider and propagator from the
+// config. Spans are exported over OTLP/gRPC to TraceAddress, which is either
+// host:port for a plaintext collector or an http:// or https:// URL. When no
+// TraceAddress is set trace context is still propagated but no spans are
+// recorded. The returned function must be called on shutdown to flush the
+// remaining spans
+func Configure(ctx context.Context, cfg config.Base) (func(context.Context) error, error) {
+
+	// Always propagate the W3C trace context so traces continue through us
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if cfg.TraceAddress == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Create the exporter, the connection is made lazily so an unavailable
+	// collector does not stop the service starting
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.TraceAddress), otlptracegrpc.WithInsecure()}
+	if strings.Contains(cfg.TraceAddress, "://") {
+		opts = []otlptracegrpc.Option{otlptracegrpc.WithEndpointURL(cfg.TraceAddress)}
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	// Describe the service the spans come from
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		semconv.DeploymentEnvironment(cfg.Env.String()),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	// Follow the caller's sampling decision, sampling new traces at the
+	// configured rate
+	batchOpts := make([]sdktrace.BatchSpanProcessorOption, 0, 1)
+	if cfg.TraceMaxBatchCount > 0 {
+		batchOpts = append(batchOpts, sdktrace.WithMaxExportBatchSize(cfg.TraceMaxBatchCount))
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, batchOpts...),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TraceSampleRate))),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"kitchen/pkg/common/config"
+
+	"go.opentelemetry.io/otel"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// receiver is an OTLP/gRPC trace receiver keeping the spans exported to it
+type receiver struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	spans []*tracepb.ResourceSpans
+}
+
+func (r *receiver) Export(_ context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, req.ResourceSpans...)
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+// received returns the spans received so far
+func (r *receiver) received() []*tracepb.ResourceSpans {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spans
+}
+
+// newReceiver starts a receiver on a local port, returning its address
+func newReceiver(t *testing.T) (*receiver, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	r := new(receiver)
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, r)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return r, listener.Addr().String()
+}
+
+func TestConfigureExportsSpans(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	r, address := newReceiver(t)
+
+	shutdown, err := Configure(context.Background(), config.Base{
+		Env:             config.Test,
+		ServiceName:     "kitchen",
+		ServiceVersion:  "1.2.3",
+		TraceAddress:    address,
+		TraceSampleRate: 1,
+	})
+	if err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	_, span := otel.Tracer("tracing.test").Start(context.Background(), "test.span")
+	span.End()
+
+	// Shutting down flushes the batched span to the receiver
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	received := r.received()
+	if len(received) != 1 {
+		t.Fatalf("received %d resource spans, want 1", len(received))
+	}
+
+	// The span is exported with the resource of the service
+	attributes := make(map[string]string)
+	for _, attribute := range received[0].GetResource().GetAttributes() {
+		attributes[attribute.Key] = attribute.GetValue().GetStringValue()
+	}
+	for key, want := range map[string]string{
+		"service.name":           "kitchen",
+		"service.version":        "1.2.3",
+		"deployment.environment": "test",
+	} {
+		if attributes[key] != want {
+			t.Errorf("resource %s = %q, want %q", key, attributes[key], want)
+		}
+	}
+	var names []string
+	for _, scope := range received[0].GetScopeSpans() {
+		for _, span := range scope.GetSpans() {
+			names = append(names, span.GetName())
+		}
+	}
+	if len(names) != 1 || names[0] != "test.span" {
+		t.Errorf("spans = %v, want [test.span]", names)
+	}
+}
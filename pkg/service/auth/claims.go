@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// NB(MLH) this type alias provides uniqueness
+type key int
+
+const (
+	claimsKey key = iota
+)
+
+// Claims are the claims of a verified bearer token
+type Claims struct {
+	jwt.RegisteredClaims
+	// Scope is the space separated list of scopes granted to the token
+	Scope string `json:"scope,omitempty"`
+	// Roles are the roles of the caller
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Scopes returns the scopes granted to the token
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope returns if the token was granted the scope
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes(), scope)
+}
+
+// HasRole returns if the caller has the role
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// FromContext gets the claims of the authenticated caller from the context.
+// It returns false for unauthenticated calls
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*Claims)
+	return claims, ok
+}
+
+// NewContext puts the claims of the authenticated caller into a context
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
@@ -0,0 +1,310 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// fetchTimeout is the deadline for fetching a key set
+	fetchTimeout = 10 * time.Second
+	// minRefetchInterval limits how often an unknown key id triggers a fetch,
+	// so tokens with made up key ids cannot hammer the issuer
+	minRefetchInterval = 30 * time.Second
+	// minRetryInterval and maxRetryInterval bound the backoff between failed
+	// fetches, doubling from the minimum with every failure
+	minRetryInterval = time.Second
+	maxRetryInterval = 5 * time.Minute
+	// maxKeySetSize is the largest key set document read
+	maxKeySetSize = 1 << 20
+)
+
+// ErrUnknownKey is returned when the key set has no key with the id
+var ErrUnknownKey = errors.New("unknown key")
+
+// keySet is a JSON Web Key Set fetched from a URL and cached, it is fetched
+// again once it is older than the refresh interval or a token is signed by a
+// key it does not know about. Fetches run in the background, one at a time and
+// without holding the lock, and back off after failures. A file:// URL reads
+// the set from disk, which is handy for local stand-ins
+type keySet struct {
+	url             *url.URL
+	client          *http.Client
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]any
+	fetchedAt   time.Time
+	nextAttempt time.Time
+	failures    int
+	err         error
+	fetching    chan struct{}
+}
+
+// newKeySet creates a key set fetched from the URL
+func newKeySet(rawURL string, client *http.Client, refreshInterval time.Duration) (*keySet, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key set url %q: %w", rawURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "file":
+	default:
+		return nil, fmt.Errorf("invalid key set url %q: unsupported scheme", rawURL)
+	}
+	return &keySet{url: u, client: client, refreshInterval: refreshInterval, now: time.Now}, nil
+}
+
+// lookup returns the key with the id, or every key when the id is empty. Stale
+// keys are served while they are refreshed, a key we do not know about waits
+// for a fetch unless fetches are backing off
+func (s *keySet) lookup(ctx context.Context, kid string) ([]any, error) {
+	s.mu.Lock()
+	if keys, ok := s.find(kid); ok {
+		if s.now().Sub(s.fetchedAt) > s.refreshInterval {
+			s.refresh()
+		}
+		s.mu.Unlock()
+		return keys, nil
+	}
+	done := s.refresh()
+	s.mu.Unlock()
+
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if keys, ok := s.find(kid); ok {
+		return keys, nil
+	}
+	if s.keys == nil && s.err != nil {
+		return nil, s.err
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+}
+
+// find returns the key with the id, or every key when the id is empty. The
+// lock must be held
+func (s *keySet) find(kid string) ([]any, bool) {
+	if kid == "" {
+		keys := make([]any, 0, len(s.keys))
+		for _, key := range s.keys {
+			keys = append(keys, key)
+		}
+		return keys, len(keys) > 0
+	}
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, false
+	}
+	return []any{key}, true
+}
+
+// refresh starts fetching the key set unless a fetch is in flight or fetches
+// are backing off, returning a channel closed once the fetch in flight
+// finishes, or nil when there is none. The lock must be held
+func (s *keySet) refresh() chan struct{} {
+	if s.fetching != nil {
+		return s.fetching
+	}
+	if s.now().Before(s.nextAttempt) {
+		return nil
+	}
+	s.fetching = make(chan struct{})
+	go s.fetch(s.fetching)
+	return s.fetching
+}
+
+// fetch fetches and parses the key set, replacing the cached keys on success
+// and backing off on failure. A failed fetch keeps serving the keys we already
+// have
+func (s *keySet) fetch(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	keys, err := s.load(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if err != nil {
+		s.failures++
+		s.err = err
+		s.nextAttempt = now.Add(retryInterval(s.failures))
+	} else {
+		s.keys, s.fetchedAt = keys, now
+		s.failures, s.err = 0, nil
+		s.nextAttempt = now.Add(minRefetchInterval)
+	}
+	s.fetching = nil
+	close(done)
+}
+
+// load reads and parses the key set
+func (s *keySet) load(ctx context.Context) (map[string]any, error) {
+	var data []byte
+	var err error
+	if s.url.Scheme == "file" {
+		data, err = os.ReadFile(s.url.Path)
+	} else {
+		data, err = s.get(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch key set %s: %w", s.url, err)
+	}
+	keys, err := parseKeySet(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key set %s: %w", s.url, err)
+	}
+	return keys, nil
+}
+
+// retryInterval returns how long to wait before fetching again after the
+// number of consecutive failures
+func retryInterval(failures int) time.Duration {
+	interval := minRetryInterval
+	for i := 1; i < failures && interval < maxRetryInterval; i++ {
+		interval *= 2
+	}
+	return min(interval, maxRetryInterval)
+}
+
+// get gets the key set over HTTP
+func (s *keySet) get(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxKeySetSize))
+}
+
+// jsonWebKey is a JSON Web Key, only the members for signature keys are
+// decoded
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseKeySet parses the signature keys of the key set by their id. Keys of
+// unsupported types are skipped
+func parseKeySet(data []byte) (map[string]any, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey decodes the public key, returning nil for unsupported key types
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+// decodeInt decodes a base64url encoded big endian integer
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("missing value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kitchen/pkg/common/logging"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// LoggerName the logger name to use for the verifier
+const LoggerName = "auth.verifier"
+
+const (
+	// DefaultRefreshInterval is how often the key sets are fetched again when
+	// no interval is configured
+	DefaultRefreshInterval = 15 * time.Minute
+	// leeway is the clock skew allowed when checking exp and nbf
+	leeway = 30 * time.Second
+)
+
+// ErrUnknownIssuer is returned when a token was issued by an issuer that is
+// not trusted
+var ErrUnknownIssuer = errors.New("unknown issuer")
+
+// signingMethods are the asymmetric algorithms accepted. Symmetric algorithms
+// are never accepted, the key sets hold public keys
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Config is the verifier config
+type Config struct {
+	// Issuers are the trusted token issuers
+	Issuers []string
+	// KeySetURLs are the JWKS URLs of the issuers, by position. Missing URLs
+	// default to the issuer's /.well-known/jwks.json
+	KeySetURLs []string
+	// Audience is the audience tokens must be issued for, tokens for any
+	// audience are accepted when it is empty
+	Audience string
+	// RefreshInterval is how often the key sets are fetched again, defaults to
+	// DefaultRefreshInterval
+	RefreshInterval time.Duration
+	// Client is the HTTP client the key sets are fetched with, defaults to
+	// http.DefaultClient
+	Client *http.Client
+}
+
+// Verifier verifies bearer JWTs against the key sets of the trusted issuers.
+// Between Start and Stop the key sets are refreshed in the background, so
+// requests are not held up fetching them
+type Verifier struct {
+	keySets         map[string]*keySet
+	parser          *jwt.Parser
+	refreshInterval time.Duration
+	logger          *zap.Logger
+
+	started atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewVerifier creates a new verifier for the configured issuers
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if len(cfg.Issuers) == 0 {
+		return nil, errors.New("no issuers configured")
+	}
+	if len(cfg.KeySetURLs) > len(cfg.Issuers) {
+		return nil, errors.New("more key set urls than issuers configured")
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = DefaultRefreshInterval
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	v := &Verifier{
+		keySets:         make(map[string]*keySet, len(cfg.Issuers)),
+		refreshInterval: cfg.RefreshInterval,
+		logger:          logging.NewLogger(LoggerName),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+	for i, issuer := range cfg.Issuers {
+		keySetURL := strings.TrimSuffix(issuer, "/") + "/.well-known/jwks.json"
+		if i < len(cfg.KeySetURLs) && cfg.KeySetURLs[i] != "" {
+			keySetURL = cfg.KeySetURLs[i]
+		}
+		set, err := newKeySet(keySetURL, cfg.Client, cfg.RefreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		v.keySets[issuer] = set
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// Start fetches the key sets, then refreshes them every refresh interval until
+// Stop is called. Key sets that cannot be fetched are logged rather than
+// failing, they are fetched again when a token needs them
+func (v *Verifier) Start(ctx context.Context) error {
+	v.refresh(ctx)
+	v.started.Store(true)
+	go v.run()
+	return nil
+}
+
+// Stop stops refreshing the key sets, returning at once when Start never
+// started refreshing
+func (v *Verifier) Stop(ctx context.Context) error {
+	select {
+	case <-v.stop:
+	default:
+		close(v.stop)
+	}
+	if !v.started.Load() {
+		return nil
+	}
+	select {
+	case <-v.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run refreshes the key sets every refresh interval
+func (v *Verifier) run() {
+	defer close(v.done)
+	ticker := time.NewTicker(v.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-v.stop:
+			return
+		case <-ticker.C:
+			v.refresh(context.Background())
+		}
+	}
+}
+
+// refresh fetches every key set, waiting for the fetches until the context is
+// done
+func (v *Verifier) refresh(ctx context.Context) {
+	for issuer, set := range v.keySets {
+		set.mu.Lock()
+		done := set.refresh()
+		set.mu.Unlock()
+		if done == nil {
+			continue
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return
+		}
+		set.mu.Lock()
+		err := set.err
+		set.mu.Unlock()
+		if err != nil {
+			v.logger.Warn("failed to refresh key set", zap.String("issuer", issuer), zap.Error(err))
+		}
+	}
+}
+
+// Verify verifies the token was signed by a trusted issuer and is valid now,
+// returning its claims
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := new(Claims)
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return v.keyFor(ctx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// keyFor looks up the keys the token may have been signed with in the key set
+// of its issuer
+func (v *Verifier) keyFor(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	issuer, err := token.Claims.GetIssuer()
+	if err != nil {
+		return nil, err
+	}
+	set, ok := v.keySets[issuer]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownIssuer, issuer)
+	}
+	kid, _ := token.Header["kid"].(string)
+	keys, err := set.lookup(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 1 {
+		return keys[0], nil
+	}
+	verificationKeys := make([]jwt.VerificationKey, 0, len(keys))
+	for _, key := range keys {
+		verificationKeys = append(verificationKeys, key)
+	}
+	return jwt.VerificationKeySet{Keys: verificationKeys}, nil
+}
@@ -0,0 +1,278 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const issuer = "https://issuer.example.com"
+
+// signingKey is a key tokens are signed with and published in a key set
+type signingKey struct {
+	kid     string
+	private *ecdsa.PrivateKey
+}
+
+// newSigningKey generates a P-256 signing key
+func newSigningKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return signingKey{kid: kid, private: private}
+}
+
+// sign signs a token for the subject expiring in an hour
+func (k signingKey) sign(t *testing.T, subject string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.private)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// keySetJSON encodes the public keys as a JSON Web Key Set
+func keySetJSON(t *testing.T, keys ...signingKey) []byte {
+	t.Helper()
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kty: "EC",
+			Kid: k.kid,
+			Use: "sig",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(k.private.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(k.private.Y.FillBytes(make([]byte, 32))),
+		})
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to encode key set: %v", err)
+	}
+	return data
+}
+
+// keySetServer serves a key set over HTTP, counting the fetches
+type keySetServer struct {
+	*httptest.Server
+	fetches atomic.Int64
+
+	mu     sync.Mutex
+	data   []byte
+	status int
+}
+
+// newKeySetServer starts a server serving the keys
+func newKeySetServer(t *testing.T, keys ...signingKey) *keySetServer {
+	t.Helper()
+	s := &keySetServer{status: http.StatusOK}
+	s.set(keySetJSON(t, keys...), http.StatusOK)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.WriteHeader(s.status)
+		_, _ = w.Write(s.data)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// set replaces the response of the server
+func (s *keySetServer) set(data []byte, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data, s.status = data, status
+}
+
+// newTestVerifier creates a verifier for the issuer with the key set URL and
+// a clock the test controls
+func newTestVerifier(t *testing.T, keySetURL string) (*Verifier, *time.Time) {
+	t.Helper()
+	v, err := NewVerifier(Config{Issuers: []string{issuer}, KeySetURLs: []string{keySetURL}})
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+	now := time.Now()
+	v.keySets[issuer].now = func() time.Time { return now }
+	return v, &now
+}
+
+func TestVerifyFileKeySet(t *testing.T) {
+	key := newSigningKey(t, "file")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, keySetJSON(t, key), 0o600); err != nil {
+		t.Fatalf("failed to write key set: %v", err)
+	}
+	v, _ := newTestVerifier(t, "file://"+path)
+
+	claims, err := v.Verify(context.Background(), key.sign(t, "alice"))
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if claims.Subject != "alice" {
+		t.Errorf("subject = %q, want alice", claims.Subject)
+	}
+
+	// A token signed by a key outside the set is refused
+	other := newSigningKey(t, "other")
+	if _, err := v.Verify(context.Background(), other.sign(t, "mallory")); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Verify with an unknown key = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestVerifyHTTPKeySet(t *testing.T) {
+	key := newSigningKey(t, "one")
+	server := newKeySetServer(t, key)
+	v, now := newTestVerifier(t, server.URL)
+	ctx := context.Background()
+
+	// The keys are fetched once and cached
+	for i := 0; i < 3; i++ {
+		if _, err := v.Verify(ctx, key.sign(t, "alice")); err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+
+	// A rotated key is not fetched again until the refetch interval passed
+	rotated := newSigningKey(t, "two")
+	server.set(keySetJSON(t, key, rotated), http.StatusOK)
+	if _, err := v.Verify(ctx, rotated.sign(t, "alice")); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Verify with a rotated key = %v, want ErrUnknownKey", err)
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+	*now = now.Add(minRefetchInterval + time.Second)
+	if _, err := v.Verify(ctx, rotated.sign(t, "alice")); err != nil {
+		t.Fatalf("Verify with a rotated key failed: %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+}
+
+func TestVerifyHTTPKeySetBacksOff(t *testing.T) {
+	key := newSigningKey(t, "one")
+	server := newKeySetServer(t, key)
+	server.set([]byte("unavailable"), http.StatusServiceUnavailable)
+	v, now := newTestVerifier(t, server.URL)
+	ctx := context.Background()
+
+	// A failed fetch is not retried until the backoff passed
+	if _, err := v.Verify(ctx, key.sign(t, "alice")); err == nil {
+		t.Fatal("Verify succeeded without a key set")
+	}
+	if _, err := v.Verify(ctx, key.sign(t, "alice")); err == nil {
+		t.Fatal("Verify succeeded without a key set")
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+
+	server.set(keySetJSON(t, key), http.StatusOK)
+	*now = now.Add(minRetryInterval + time.Second)
+	if _, err := v.Verify(ctx, key.sign(t, "alice")); err != nil {
+		t.Fatalf("Verify failed after the backoff: %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+}
+
+func TestVerifierStartFetchesKeySets(t *testing.T) {
+	key := newSigningKey(t, "one")
+	server := newKeySetServer(t, key)
+	v, _ := newTestVerifier(t, server.URL)
+
+	if err := v.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := v.Stop(context.Background()); err != nil {
+			t.Errorf("Stop failed: %v", err)
+		}
+	})
+	if got := server.fetches.Load(); got != 1 {
+		t.Fatalf("fetches after Start = %d, want 1", got)
+	}
+	if _, err := v.Verify(context.Background(), key.sign(t, "alice")); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+}
+
+func TestRetryInterval(t *testing.T) {
+	for failures, want := range map[int]time.Duration{
+		1:  minRetryInterval,
+		2:  2 * minRetryInterval,
+		4:  8 * minRetryInterval,
+		20: maxRetryInterval,
+	} {
+		if got := retryInterval(failures); got != want {
+			t.Errorf("retryInterval(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
+
+// stopWithin stops the verifier, failing when it takes longer than a second
+func stopWithin(t *testing.T, v *Verifier) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := v.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+}
+
+func TestVerifierStopWithoutStart(t *testing.T) {
+	v, _ := newTestVerifier(t, "file:///nonexistent/jwks.json")
+	stopWithin(t, v)
+	stopWithin(t, v)
+}
+
+func TestVerifierStopAfterFailedStart(t *testing.T) {
+	server := newKeySetServer(t)
+	server.set([]byte("unavailable"), http.StatusServiceUnavailable)
+	v, _ := newTestVerifier(t, server.URL)
+
+	// The initial fetch fails, and a cancelled start gives up waiting on it
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := v.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	stopWithin(t, v)
+}
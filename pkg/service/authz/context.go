@@ -0,0 +1,37 @@
+package authz
+
+import (
+	"context"
+	common_errors "kitchen/pkg/common/errors"
+)
+
+// NB(MLH) this type alias provides uniqueness
+type key int
+
+const (
+	ownerKey key = iota
+)
+
+// OwnerFromContext returns the subject the resources a call acts on must be
+// owned by, ok is false when the call is not restricted to an owner
+func OwnerFromContext(ctx context.Context) (string, bool) {
+	owner, ok := ctx.Value(ownerKey).(string)
+	return owner, ok
+}
+
+// NewOwnerContext returns a new context restricting the call to resources
+// owned by the subject
+func NewOwnerContext(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey, owner)
+}
+
+// CheckOwner returns a PermissionDenied domain error when the call is
+// restricted to resources of an owner other than the supplied one. Handlers
+// call it with the owner of the stored resource, which unlike the request
+// cannot be made up by the caller
+func CheckOwner(ctx context.Context, owner string) error {
+	if required, ok := OwnerFromContext(ctx); ok && owner != required {
+		return common_errors.PermissionDenied("permission denied").WithReason("NOT_OWNER")
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package authz
+
+import (
+	"fmt"
+	"kitchen/pkg/service/auth"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Rule is the authorization rule of a single procedure. Every requirement set
+// on the rule must be met for a call to be allowed
+type Rule struct {
+	// Procedure is the procedure the rule applies to, such as
+	// /kitchen.v1.KitchenService/UpdatePost
+	Procedure string `config:"procedure"`
+	// Roles the caller must hold one of
+	Roles []string `config:"roles"`
+	// Scopes the caller must hold all of
+	Scopes []string `config:"scopes"`
+	// Owner is the path to a string field of the request message, such as
+	// user_id, that must equal the subject of the caller. It suits requests
+	// naming the owner of a resource they create, the fields of requests
+	// acting on a stored resource are made up by the caller, use Owned for
+	// those
+	Owner string `config:"owner"`
+	// Owned restricts the call to stored resources owned by the caller. The
+	// policy cannot load the resource, so the handler checks its owner with
+	// CheckOwner
+	Owned bool `config:"owned"`
+	// OwnerExemptRoles are roles that may act on resources they do not own,
+	// such as admin
+	OwnerExemptRoles []string `config:"owner_exempt_roles"`
+}
+
+// Decision is the outcome of authorizing a call
+type Decision struct {
+	Allowed bool
+	// Reason explains the decision for the audit log
+	Reason string
+	// Owner is the subject the resources acted on must be owned by, empty
+	// when the call is not restricted to an owner
+	Owner string
+}
+
+// allow returns an allowing decision
+func allow(reason string) Decision {
+	return Decision{Allowed: true, Reason: reason}
+}
+
+// deny returns a denying decision
+func deny(format string, args ...any) Decision {
+	return Decision{Reason: fmt.Sprintf(format, args...)}
+}
+
+// rule is a validated Rule with its owner field path resolved against the
+// request message
+type rule struct {
+	Rule
+	input protoreflect.FullName
+	owner []protoreflect.FieldDescriptor
+}
+
+// Policy authorizes calls against the rule of their procedure
+type Policy struct {
+	rules map[string]rule
+	// defaultDeny denies procedures without a rule rather than allowing them
+	defaultDeny bool
+}
+
+// NewPolicy creates a policy from the rules. The procedures must be registered
+// in the global proto registry, so typos cannot leave a procedure unguarded.
+// Procedures without a rule are allowed, unless defaultDeny is set
+func NewPolicy(rules []Rule, defaultDeny bool) (*Policy, error) {
+	p := &Policy{rules: make(map[string]rule, len(rules)), defaultDeny: defaultDeny}
+	for _, r := range rules {
+		if _, ok := p.rules[r.Procedure]; ok {
+			return nil, fmt.Errorf("duplicate rule for procedure %q", r.Procedure)
+		}
+		method, err := findMethod(r.Procedure)
+		if err != nil {
+			return nil, err
+		}
+		compiled := rule{Rule: r, input: method.Input().FullName()}
+		if r.Owner != "" {
+			if compiled.owner, err = resolveField(method.Input(), r.Owner); err != nil {
+				return nil, fmt.Errorf("invalid owner of procedure %q: %w", r.Procedure, err)
+			}
+		}
+		p.rules[r.Procedure] = compiled
+	}
+	return p, nil
+}
+
+// Authorize decides if the caller may call the procedure with the request.
+// Claims are nil for unauthenticated callers, and the request is nil when it is
+// not known up front, such as for streaming calls
+func (p *Policy) Authorize(procedure string, claims *auth.Claims, request proto.Message) Decision {
+	r, ok := p.rules[procedure]
+	if !ok {
+		if p.defaultDeny {
+			return deny("no rule for procedure")
+		}
+		return allow("no rule for procedure")
+	}
+	if len(r.Roles) == 0 && len(r.Scopes) == 0 && r.Owner == "" && !r.Owned {
+		return allow("rule has no requirements")
+	}
+	if claims == nil {
+		return deny("caller is not authenticated")
+	}
+	if len(r.Roles) > 0 && !slices.ContainsFunc(r.Roles, claims.HasRole) {
+		return deny("caller has none of the roles %s", strings.Join(r.Roles, ", "))
+	}
+	for _, scope := range r.Scopes {
+		if !claims.HasScope(scope) {
+			return deny("caller lacks the scope %s", scope)
+		}
+	}
+	if r.Owner == "" && !r.Owned {
+		return allow("caller meets the requirements")
+	}
+	if role := slices.IndexFunc(r.OwnerExemptRoles, claims.HasRole); role >= 0 {
+		return allow("caller has the owner exempt role " + r.OwnerExemptRoles[role])
+	}
+	if claims.Subject == "" {
+		return deny("caller has no subject to own the resource")
+	}
+	if r.Owner != "" {
+		if request == nil || request.ProtoReflect().Descriptor().FullName() != r.input {
+			return deny("ownership cannot be checked without the request")
+		}
+		switch owner := fieldValue(request.ProtoReflect(), r.owner); {
+		case owner == "":
+			return deny("request has no %s", r.Owner)
+		case owner != claims.Subject:
+			return deny("caller does not own the resource")
+		}
+	}
+	if r.Owned {
+		return Decision{Allowed: true, Reason: "caller must own the resource", Owner: claims.Subject}
+	}
+	return allow("caller owns the resource")
+}
+
+// findMethod finds the method of a procedure in the global proto registry
+func findMethod(procedure string) (protoreflect.MethodDescriptor, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(procedure, "/"), "/")
+	if !ok || !strings.HasPrefix(procedure, "/") {
+		return nil, fmt.Errorf("invalid procedure %q, must be of the form /package.Service/Method", procedure)
+	}
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service of procedure %q: %w", procedure, err)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unknown service of procedure %q: %s is not a service", procedure, service)
+	}
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(method))
+	if methodDescriptor == nil {
+		return nil, fmt.Errorf("unknown procedure %q", procedure)
+	}
+	return methodDescriptor, nil
+}
+
+// resolveField resolves a dotted path to a singular string field of the
+// message, descending through singular message fields
+func resolveField(message protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	names := strings.Split(path, ".")
+	fields := make([]protoreflect.FieldDescriptor, 0, len(names))
+	for i, name := range names {
+		field := message.Fields().ByName(protoreflect.Name(name))
+		if field == nil {
+			return nil, fmt.Errorf("%s has no field %q", message.FullName(), name)
+		}
+		if field.Cardinality() == protoreflect.Repeated {
+			return nil, fmt.Errorf("field %q is repeated", name)
+		}
+		fields = append(fields, field)
+		if i == len(names)-1 {
+			if field.Kind() != protoreflect.StringKind {
+				return nil, fmt.Errorf("field %q is not a string", name)
+			}
+			break
+		}
+		if field.Message() == nil {
+			return nil, fmt.Errorf("field %q is not a message", name)
+		}
+		message = field.Message()
+	}
+	return fields, nil
+}
+
+// fieldValue gets the value of the string field at the resolved path, which is
+// empty when the field or any message on the way to it is unset
+func fieldValue(message protoreflect.Message, path []protoreflect.FieldDescriptor) string {
+	for _, field := range path[:len(path)-1] {
+		if !message.Has(field) {
+			return ""
+		}
+		message = message.Get(field).Message()
+	}
+	return message.Get(path[len(path)-1]).String()
+}
@@ -0,0 +1,53 @@
+package authz
+
+import (
+	"testing"
+
+	"kitchen/pkg/service/auth"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+	"kitchen/proto/gen/kitchen/v1/kitchenv1connect"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// claimsFor returns the claims of the subject with the roles
+func claimsFor(subject string, roles ...string) *auth.Claims {
+	return &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: subject}, Roles: roles}
+}
+
+func TestAuthorizeOwnership(t *testing.T) {
+	policy, err := NewPolicy([]Rule{
+		{Procedure: kitchenv1connect.KitchenServiceCreatePostProcedure, Owner: "user_id"},
+		{Procedure: kitchenv1connect.KitchenServiceDeletePostProcedure, Owned: true, OwnerExemptRoles: []string{"admin"}},
+	}, true)
+	if err != nil {
+		t.Fatalf("NewPolicy failed: %v", err)
+	}
+	create := kitchenv1connect.KitchenServiceCreatePostProcedure
+	remove := kitchenv1connect.KitchenServiceDeletePostProcedure
+
+	for _, test := range []struct {
+		name      string
+		procedure string
+		claims    *auth.Claims
+		request   proto.Message
+		allowed   bool
+		owner     string
+	}{
+		{"create as self", create, claimsFor("alice"), &kitchenv1.CreatePostRequest{UserId: "alice"}, true, ""},
+		{"create as another", create, claimsFor("alice"), &kitchenv1.CreatePostRequest{UserId: "bob"}, false, ""},
+		{"create anonymously", create, nil, &kitchenv1.CreatePostRequest{UserId: "alice"}, false, ""},
+		{"delete restricted to the caller", remove, claimsFor("alice"), &kitchenv1.DeletePostRequest{Id: "1"}, true, "alice"},
+		{"delete as admin", remove, claimsFor("root", "admin"), &kitchenv1.DeletePostRequest{Id: "1"}, true, ""},
+		{"delete without subject", remove, claimsFor(""), &kitchenv1.DeletePostRequest{Id: "1"}, false, ""},
+		{"no rule", kitchenv1connect.KitchenServiceGetPostProcedure, claimsFor("alice"), nil, false, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			decision := policy.Authorize(test.procedure, test.claims, test.request)
+			if decision.Allowed != test.allowed || decision.Owner != test.owner {
+				t.Errorf("Authorize = %+v, want allowed %t owner %q", decision, test.allowed, test.owner)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+)
+
+// NB(MLH) this type alias provides uniqueness
+type key int
+
+const (
+	peerKey key = iota
+)
+
+// Peer is the identity of a caller that presented a verified client
+// certificate
+type Peer struct {
+	// Subject is the common name of the certificate subject
+	Subject string
+	// DNSNames and URIs are the subject alternative names of the certificate,
+	// URIs hold SPIFFE ids
+	DNSNames []string
+	URIs     []string
+	// Certificate is the verified client certificate
+	Certificate *x509.Certificate
+}
+
+// Name returns the most specific name of the peer, the first URI, DNS name or
+// the subject, in that order
+func (p *Peer) Name() string {
+	if len(p.URIs) > 0 {
+		return p.URIs[0]
+	}
+	if len(p.DNSNames) > 0 {
+		return p.DNSNames[0]
+	}
+	return p.Subject
+}
+
+// PeerFromContext gets the identity of the caller from the context. It returns
+// false when the caller presented no verified client certificate
+func PeerFromContext(ctx context.Context) (*Peer, bool) {
+	peer, ok := ctx.Value(peerKey).(*Peer)
+	return peer, ok
+}
+
+// NewPeerContext puts the identity of the caller into a context
+func NewPeerContext(ctx context.Context, peer *Peer) context.Context {
+	return context.WithValue(ctx, peerKey, peer)
+}
+
+// Middleware puts the identity of callers presenting a verified client
+// certificate into the request context
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cert := r.TLS.VerifiedChains[0][0]
+		peer := &Peer{
+			Subject:     cert.Subject.CommonName,
+			DNSNames:    cert.DNSNames,
+			URIs:        make([]string, 0, len(cert.URIs)),
+			Certificate: cert,
+		}
+		for _, uri := range cert.URIs {
+			peer.URIs = append(peer.URIs, uri.String())
+		}
+		next.ServeHTTP(w, r.WithContext(NewPeerContext(r.Context(), peer)))
+	})
+}
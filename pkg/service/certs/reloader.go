@@ -0,0 +1,212 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"kitchen/pkg/common/logging"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LoggerName the logger name to use for the reloader
+const LoggerName = "certs.reloader"
+
+// Config is the reloader config
+type Config struct {
+	// CertFile and KeyFile are the PEM encoded serving certificate chain and
+	// its private key
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the PEM encoded bundle of CAs client certificates are
+	// verified against. Client certificates are not requested when it is
+	// empty
+	ClientCAFile string
+	// RequireClientCert rejects connections without a verified client
+	// certificate, rather than falling back to bearer tokens
+	RequireClientCert bool
+	// ReloadInterval is how often the files are checked for changes
+	ReloadInterval time.Duration
+}
+
+// fileStamp identifies a version of a file on disk
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader serves a TLS certificate and client CA bundle loaded from disk,
+// checking the files for changes periodically and reloading them without a
+// restart. Files replaced through symlinks, as Kubernetes does with mounted
+// secrets, are picked up too. A failed reload keeps serving what was loaded
+// before
+type Reloader struct {
+	cfg    Config
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stamps    map[string]fileStamp
+
+	started atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewReloader creates a new reloader, the files are loaded by Start
+func NewReloader(cfg Config) (*Reloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("certificate and key files are required")
+	}
+	if cfg.RequireClientCert && cfg.ClientCAFile == "" {
+		return nil, errors.New("a client ca file is required to require client certificates")
+	}
+	if cfg.ReloadInterval <= 0 {
+		return nil, errors.New("reload interval must be positive")
+	}
+	return &Reloader{
+		cfg:    cfg,
+		logger: logging.NewLogger(LoggerName),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Start loads the files, failing if they cannot be loaded, then checks them
+// for changes until Stop is called
+func (r *Reloader) Start(context.Context) error {
+	if _, err := r.reload(); err != nil {
+		return err
+	}
+	r.started.Store(true)
+	go r.run()
+	return nil
+}
+
+// Stop stops checking the files for changes, returning at once when Start
+// never started checking
+func (r *Reloader) Stop(ctx context.Context) error {
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	if !r.started.Load() {
+		return nil
+	}
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run checks the files for changes every reload interval
+func (r *Reloader) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				r.logger.Error("failed to reload certificates, serving the previous ones", zap.Error(err))
+			} else if reloaded {
+				r.logger.Info("reloaded certificates")
+			}
+		}
+	}
+}
+
+// reload loads the files when any of them changed since they were last
+// loaded, returning if they were
+func (r *Reloader) reload() (bool, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	stamps := make(map[string]fileStamp, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	r.mu.RLock()
+	changed := len(r.stamps) != len(stamps)
+	for file, stamp := range stamps {
+		changed = changed || r.stamps[file] != stamp
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	var clientCAs *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to load client ca: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("failed to load client ca: no certificates in %s", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert, r.clientCAs, r.stamps = &cert, clientCAs, stamps
+	return true, nil
+}
+
+// TLSConfig returns a server TLS config serving the current certificate and
+// verifying client certificates against the current client CAs
+func (r *Reloader) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.cert, nil
+		},
+	}
+	if r.cfg.ClientCAFile == "" {
+		return base
+	}
+
+	// The client CAs can only be swapped by handing out a config per
+	// connection
+	clientAuth := tls.VerifyClientCertIfGiven
+	if r.cfg.RequireClientCert {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		cfg := base.Clone()
+		cfg.GetConfigForClient = nil
+		cfg.ClientAuth = clientAuth
+		cfg.ClientCAs = r.clientCAs
+		return cfg, nil
+	}
+	return base
+}
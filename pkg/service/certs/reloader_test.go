@@ -0,0 +1,129 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a self-signed certificate for the common name and
+// its key to the files
+func writeCertificate(t *testing.T, commonName, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	// Move the modification time on, so a rewrite within the resolution of
+	// the file system is noticed too
+	modTime := time.Now().Add(time.Duration(len(commonName)) * time.Second)
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatalf("failed to touch %s: %v", file, err)
+		}
+	}
+}
+
+// servedName returns the common name of the certificate the config serves
+func servedName(t *testing.T, cfg *tls.Config) string {
+	t.Helper()
+	cert, err := cfg.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil || cert == nil {
+		t.Fatalf("GetCertificate = %v, %v", cert, err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+// newTestReloader creates a reloader of certificate files in a temporary
+// directory, which are not written
+func newTestReloader(t *testing.T) (*Reloader, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	r, err := NewReloader(Config{CertFile: certFile, KeyFile: keyFile, ReloadInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewReloader failed: %v", err)
+	}
+	return r, certFile, keyFile
+}
+
+// stopWithin stops the reloader, failing when it takes longer than a second
+func stopWithin(t *testing.T, r *Reloader) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := r.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+}
+
+func TestReloaderReloads(t *testing.T) {
+	r, certFile, keyFile := newTestReloader(t)
+	writeCertificate(t, "one", certFile, keyFile)
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	cfg := r.TLSConfig()
+	if got := servedName(t, cfg); got != "one" {
+		t.Fatalf("served %q, want one", got)
+	}
+
+	writeCertificate(t, "two", certFile, keyFile)
+	deadline := time.Now().Add(5 * time.Second)
+	for servedName(t, cfg) != "two" {
+		if time.Now().After(deadline) {
+			t.Fatal("the rewritten certificate was not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stopWithin(t, r)
+}
+
+func TestReloaderStopWithoutStart(t *testing.T) {
+	r, _, _ := newTestReloader(t)
+	stopWithin(t, r)
+	stopWithin(t, r)
+}
+
+func TestReloaderStopAfterFailedStart(t *testing.T) {
+	r, _, _ := newTestReloader(t)
+	if err := r.Start(context.Background()); err == nil {
+		t.Fatal("Start succeeded without certificate files")
+	}
+	stopWithin(t, r)
+}
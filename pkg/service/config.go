@@ -6,7 +6,12 @@ import (
 	"fmt"
 	"kitchen/pkg/common/config"
 	"kitchen/pkg/common/logging"
+	"kitchen/pkg/service/authz"
+	"kitchen/pkg/service/cors"
+	"kitchen/pkg/service/ratelimit"
+	"maps"
 	"math"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -19,6 +24,12 @@ func init() {
 	config.RegisterDefault("read_header_timeout", time.Second)
 	config.RegisterDefault("read_timeout", 30*time.Second)
 	config.RegisterDefault("write_timeout", 30*time.Second)
+	config.RegisterDefault("shutdown_drain_delay", 5*time.Second)
+	config.RegisterDefault("shutdown_timeout", 30*time.Second)
+	config.RegisterDefault("log_payload_max_size", 1024)
+	config.RegisterDefault("jwks_refresh_interval", 15*time.Minute)
+	config.RegisterDefault("tls_reload_interval", time.Minute)
+	config.RegisterDefault("idempotency_key_ttl", 24*time.Hour)
 }
 
 // Ensure Config conforms to ValidatableConfig
@@ -53,13 +64,65 @@ func LoadAndValidateConfig(ctx context.Context, flags *pflag.FlagSet, c Validata
 
 // Config is the gRPC config struct
 type Config struct {
-	config.Base       `config:",squash"`
-	ValidIssuers      []string      `config:"valid_issuers"`
+	config.Base `config:",squash"`
+	// CORS is the CORS policy of browser callers
+	CORS cors.Config `config:",squash"`
+	// ValidIssuers are the issuers bearer tokens are accepted from. Callers
+	// must present a token from one of them when any are configured
+	ValidIssuers []string `config:"valid_issuers"`
+	// JWKSURLs are the key set URLs of the ValidIssuers, by position. Missing
+	// URLs default to the issuer's /.well-known/jwks.json, a file:// URL reads
+	// the key set from disk
+	JWKSURLs            []string      `config:"jwks_urls"`
+	JWKSRefreshInterval time.Duration `config:"jwks_refresh_interval"`
+	// JWTAudience is the audience bearer tokens must be issued for
+	JWTAudience string `config:"jwt_audience"`
+	// AuthzRules are the authorization rules of the procedures, which are
+	// lists of maps and so can only be set in the config file
+	AuthzRules []authz.Rule `config:"authz_rules"`
+	// AuthzDefaultDeny denies procedures without an authorization rule rather
+	// than allowing them
+	AuthzDefaultDeny bool `config:"authz_default_deny"`
+	// RateLimits are the rate limits of the procedures, which are lists of
+	// maps and so can only be set in the config file
+	RateLimits []ratelimit.Rule `config:"rate_limits"`
+	// RateLimitTrustedProxies are the IPs or CIDR prefixes of the load
+	// balancers in front of the service. The client IP is taken from the
+	// X-Forwarded-For header of their connections, and from the connection
+	// otherwise
+	RateLimitTrustedProxies []string `config:"rate_limit_trusted_proxies"`
+	// IdempotencyKeyTTL is how long the response to a request with an
+	// Idempotency-Key is replayed to retries
+	IdempotencyKeyTTL time.Duration `config:"idempotency_key_ttl"`
+	// TLSCertFile and TLSKeyFile are the serving certificate and key, the
+	// GrpcPort serves h2c plaintext when they are empty
+	TLSCertFile string `config:"tls_cert_file"`
+	TLSKeyFile  string `config:"tls_key_file"`
+	// TLSClientCAFile is the CA bundle client certificates are verified
+	// against, enabling mutual TLS
+	TLSClientCAFile string `config:"tls_client_ca_file"`
+	// TLSRequireClientCert rejects callers without a verified client
+	// certificate, rather than letting them present a bearer token
+	TLSRequireClientCert bool `config:"tls_require_client_cert"`
+	// TLSReloadInterval is how often the certificate files are checked for
+	// changes
+	TLSReloadInterval time.Duration `config:"tls_reload_interval"`
 	GrpcPort          int           `config:"grpc_port"`
 	HttpPort          int           `config:"http_port"`
 	ReadHeaderTimeout time.Duration `config:"read_header_timeout"`
 	ReadTimeout       time.Duration `config:"read_timeout"`
 	WriteTimeout      time.Duration `config:"write_timeout"`
+	// ShutdownDrainDelay is how long the service keeps serving after it is
+	// signalled to stop and reports not-ready, giving load balancers time to
+	// stop sending it traffic
+	ShutdownDrainDelay time.Duration `config:"shutdown_drain_delay"`
+	// ShutdownTimeout is the deadline for in-flight requests to complete and
+	// the shutdown hooks to run, remaining connections are then closed
+	ShutdownTimeout time.Duration `config:"shutdown_timeout"`
+	// LogPayloads logs the request and response messages of unary calls in
+	// the access log, truncated to LogPayloadMaxSize bytes
+	LogPayloads       bool `config:"log_payloads"`
+	LogPayloadMaxSize int  `config:"log_payload_max_size"`
 }
 
 // Root returns the root config
@@ -80,10 +143,53 @@ func (c Config) Validate(ctx context.Context) error {
 	if err := validatePort(c.HttpPort); err != nil {
 		violations["http_port"] = err
 	}
-	if len(violations) > 0 {
-		return errors.New("validation failed")
+	if c.ShutdownDrainDelay < 0 {
+		violations["shutdown_drain_delay"] = errors.New("must not be negative")
 	}
-	return nil
+	if c.ShutdownTimeout <= 0 {
+		violations["shutdown_timeout"] = errors.New("must be positive")
+	}
+	if len(c.JWKSURLs) > len(c.ValidIssuers) {
+		violations["jwks_urls"] = errors.New("must not have more entries than valid_issuers")
+	}
+	if c.JWKSRefreshInterval <= 0 {
+		violations["jwks_refresh_interval"] = errors.New("must be positive")
+	}
+	if _, err := authz.NewPolicy(c.AuthzRules, c.AuthzDefaultDeny); err != nil {
+		violations["authz_rules"] = err
+	}
+	if err := ratelimit.ValidateRules(c.RateLimits); err != nil {
+		violations["rate_limits"] = err
+	}
+	if _, err := ratelimit.ParseTrustedProxies(c.RateLimitTrustedProxies); err != nil {
+		violations["rate_limit_trusted_proxies"] = err
+	}
+	maps.Copy(violations, c.CORS.Validate(c.Env))
+	if c.IdempotencyKeyTTL <= 0 {
+		violations["idempotency_key_ttl"] = errors.New("must be positive")
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		violations["tls_cert_file"] = errors.New("must be set together with tls_key_file")
+	}
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" {
+		violations["tls_client_ca_file"] = errors.New("requires tls_cert_file")
+	}
+	if c.TLSRequireClientCert && c.TLSClientCAFile == "" {
+		violations["tls_require_client_cert"] = errors.New("requires tls_client_ca_file")
+	}
+	if c.TLSReloadInterval <= 0 {
+		violations["tls_reload_interval"] = errors.New("must be positive")
+	}
+	if c.LogPayloadMaxSize < 0 {
+		violations["log_payload_max_size"] = errors.New("must not be negative")
+	}
+
+	// Report every violation, by config key in a stable order
+	errs := make([]error, 0, len(violations))
+	for _, key := range slices.Sorted(maps.Keys(violations)) {
+		errs = append(errs, fmt.Errorf("%s: %w", key, violations[key]))
+	}
+	return errors.Join(errs...)
 }
 
 // validatePort validates that the port number is in a valid range
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// validConfig returns a config passing validation
+func validConfig() Config {
+	return Config{
+		GrpcPort:            50051,
+		HttpPort:            8080,
+		ShutdownTimeout:     30 * time.Second,
+		JWKSRefreshInterval: 15 * time.Minute,
+		IdempotencyKeyTTL:   24 * time.Hour,
+		TLSReloadInterval:   time.Minute,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(context.Background()); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	// Every violation is reported with its config key, in key order
+	cfg := validConfig()
+	cfg.ShutdownTimeout = 0
+	cfg.HttpPort = 0
+	cfg.TLSClientCAFile = "ca.pem"
+	err := cfg.Validate(context.Background())
+	if err == nil {
+		t.Fatal("Validate succeeded with an invalid config")
+	}
+	want := "http_port: invalid port 0, must be in range 1 - 65535\n" +
+		"shutdown_timeout: must be positive\n" +
+		"tls_client_ca_file: requires tls_cert_file"
+	if err.Error() != want {
+		t.Errorf("Validate = %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/service/auth"
+	"kitchen/pkg/service/certs"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PeerRole is the role of callers authenticated by a verified client
+// certificate, so authorization rules can allow services calling over mTLS
+const PeerRole = "mtls_peer"
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// Verifier verifies bearer tokens, returning the claims of valid tokens
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*auth.Claims, error)
+}
+
+// Interceptor is an authentication interceptor that requires a valid bearer
+// JWT in the Authorization header of every call, putting its claims into the
+// context. Callers that presented a verified client certificate need no token,
+// their identity is put into the context as claims with the peer name as the
+// subject and the PeerRole. Calls without a valid token are
+// rejected with an Unauthenticated domain error, which the error interceptor
+// converts to CodeUnauthenticated. Without a verifier no tokens are accepted,
+// only client certificates authenticate callers and the others continue
+// anonymously, for the authorization rules to decide on
+type Interceptor struct {
+	verifier Verifier
+	exempt   func(procedure string) bool
+}
+
+// NewInterceptor creates a new connect authentication interceptor. Calls to
+// procedures exempt returns true for are let through without a token, a nil
+// exempt defaults to DefaultExempt. The verifier is nil when only client
+// certificates authenticate callers
+func NewInterceptor(verifier Verifier, exempt func(procedure string) bool) *Interceptor {
+	if exempt == nil {
+		exempt = DefaultExempt
+	}
+	return &Interceptor{verifier: verifier, exempt: exempt}
+}
+
+// DefaultExempt exempts the health and reflection services, which are called
+// by infrastructure and tooling that hold no tokens
+func DefaultExempt(procedure string) bool {
+	return strings.HasPrefix(procedure, "/grpc.health.") || strings.HasPrefix(procedure, "/grpc.reflection.")
+}
+
+// WrapUnary wraps a unary call authenticating the caller
+func (i *Interceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		if request.Spec().IsClient || i.exempt(request.Spec().Procedure) {
+			return fn(ctx, request)
+		}
+		ctx, err := i.authenticate(ctx, request.Header())
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, request)
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *Interceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler wraps a streaming handler call authenticating the
+// caller
+func (i *Interceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if i.exempt(conn.Spec().Procedure) {
+			return fn(ctx, conn)
+		}
+		ctx, err := i.authenticate(ctx, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return fn(ctx, conn)
+	}
+}
+
+// authenticate verifies the bearer token in the headers, or the client
+// certificate when there is none, returning a context holding the claims of the
+// caller
+func (i *Interceptor) authenticate(ctx context.Context, header http.Header) (context.Context, error) {
+	if peer, ok := certs.PeerFromContext(ctx); ok && (header.Get("Authorization") == "" || i.verifier == nil) {
+		return auth.NewContext(ctx, peerClaims(peer)), nil
+	}
+	if i.verifier == nil {
+		return ctx, nil
+	}
+	scheme, token, ok := strings.Cut(header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, common_errors.Unauthenticated("missing bearer token").WithReason("MISSING_TOKEN")
+	}
+	claims, err := i.verifier.Verify(ctx, strings.TrimSpace(token))
+	if err != nil {
+		return nil, common_errors.Unauthenticated("invalid bearer token").WithReason("INVALID_TOKEN").WithCause(err)
+	}
+	return auth.NewContext(ctx, claims), nil
+}
+
+// peerClaims returns the claims of a caller authenticated by a verified client
+// certificate
+func peerClaims(peer *certs.Peer) *auth.Claims {
+	return &auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: peer.Name()},
+		Roles:            []string{PeerRole},
+	}
+}
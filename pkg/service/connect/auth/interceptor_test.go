@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"kitchen/pkg/service/auth"
+	"kitchen/pkg/service/authz"
+	"kitchen/pkg/service/certs"
+	connect_authz "kitchen/pkg/service/connect/authz"
+	connect_errors "kitchen/pkg/service/connect/errors"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+	"kitchen/proto/gen/kitchen/v1/kitchenv1connect"
+
+	"connectrpc.com/connect"
+)
+
+const peerURI = "spiffe://kitchen/worker"
+
+// rejectingVerifier rejects every token
+type rejectingVerifier struct{}
+
+func (rejectingVerifier) Verify(context.Context, string) (*auth.Claims, error) {
+	return nil, errors.New("no tokens are valid")
+}
+
+// kitchenService answers GetPost with a post owned by the caller
+type kitchenService struct {
+	kitchenv1connect.UnimplementedKitchenServiceHandler
+}
+
+func (kitchenService) GetPost(ctx context.Context, req *connect.Request[kitchenv1.GetPostRequest]) (*connect.Response[kitchenv1.GetPostResponse], error) {
+	claims, _ := auth.FromContext(ctx)
+	return connect.NewResponse(&kitchenv1.GetPostResponse{Post: &kitchenv1.Post{Id: req.Msg.Id, UserId: claims.Subject}}), nil
+}
+
+// newCertificate creates a certificate from the template signed by the parent,
+// self-signed when the parent is nil
+func newCertificate(t *testing.T, template *x509.Certificate, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Minute)
+	template.NotAfter = time.Now().Add(time.Hour)
+	signer, signerKey := template, any(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// TestPeerChain runs calls through the certificate middleware and the
+// authentication, authorization and error interceptors, as the server does,
+// with and without a token verifier
+func TestPeerChain(t *testing.T) {
+	t.Run("tokens", func(t *testing.T) {
+		testPeerChain(t, rejectingVerifier{}, connect.CodeUnauthenticated)
+	})
+	t.Run("certificates only", func(t *testing.T) {
+		testPeerChain(t, nil, connect.CodePermissionDenied)
+	})
+}
+
+// testPeerChain checks a peer is authorized by its certificate, and a caller
+// without credentials is rejected with the code
+func testPeerChain(t *testing.T, verifier Verifier, anonymousCode connect.Code) {
+	ca := newCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "kitchen test ca"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	spiffeID, _ := url.Parse(peerURI)
+	client := newCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "worker"},
+		URIs:        []*url.URL{spiffeID},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &ca)
+
+	policy, err := authz.NewPolicy([]authz.Rule{
+		{Procedure: kitchenv1connect.KitchenServiceGetPostProcedure, Roles: []string{PeerRole}},
+	}, true)
+	if err != nil {
+		t.Fatalf("NewPolicy failed: %v", err)
+	}
+	path, handler := kitchenv1connect.NewKitchenServiceHandler(kitchenService{}, connect.WithInterceptors(
+		connect_errors.NewInterceptor("kitchen.test"),
+		NewInterceptor(verifier, nil),
+		connect_authz.NewInterceptor(policy),
+	))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Leaf)
+	server := httptest.NewUnstartedServer(certs.Middleware(mux))
+	server.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	newClient := func(certificates ...tls.Certificate) kitchenv1connect.KitchenServiceClient {
+		transport := server.Client().Transport.(*http.Transport).Clone()
+		transport.TLSClientConfig.Certificates = certificates
+		return kitchenv1connect.NewKitchenServiceClient(&http.Client{Transport: transport}, server.URL)
+	}
+	request := connect.NewRequest(&kitchenv1.GetPostRequest{Id: "1"})
+
+	// A peer with a verified certificate is authorized by its role, with its
+	// name as the subject
+	resp, err := newClient(client).GetPost(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GetPost with a client certificate failed: %v", err)
+	}
+	if resp.Msg.Post.UserId != peerURI {
+		t.Errorf("subject = %q, want %q", resp.Msg.Post.UserId, peerURI)
+	}
+
+	// A caller without a certificate or token is rejected
+	_, err = newClient().GetPost(context.Background(), request)
+	if code := connect.CodeOf(err); code != anonymousCode {
+		t.Errorf("GetPost without credentials = %v, want %v", code, anonymousCode)
+	}
+}
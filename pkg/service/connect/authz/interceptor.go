@@ -0,0 +1,93 @@
+package authz
+
+import (
+	"context"
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/common/logging"
+	"kitchen/pkg/service/auth"
+	"kitchen/pkg/service/authz"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// LoggerName the logger name to use for the authorization audit log
+const LoggerName = "authz"
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// Interceptor is an authorization interceptor that enforces the policy rule of
+// each procedure, using the claims the authentication interceptor put into the
+// context. Every decision is logged for auditing, and denied calls are rejected
+// with a PermissionDenied domain error, which the error interceptor converts
+// to CodePermissionDenied. Calls restricted to resources of their caller carry
+// the owner in the context for the handler to check. It must run inside the
+// authentication interceptor
+type Interceptor struct {
+	policy *authz.Policy
+}
+
+// NewInterceptor creates a new connect authorization interceptor
+func NewInterceptor(policy *authz.Policy) *Interceptor {
+	return &Interceptor{policy: policy}
+}
+
+// WrapUnary wraps a unary call authorizing the caller
+func (i *Interceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		if request.Spec().IsClient {
+			return fn(ctx, request)
+		}
+		message, _ := request.Any().(proto.Message)
+		ctx, err := i.authorize(ctx, request.Spec().Procedure, message)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, request)
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *Interceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler wraps a streaming handler call authorizing the caller
+// before any message is received, so ownership rules deny streaming calls
+func (i *Interceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authorize(ctx, conn.Spec().Procedure, nil)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, conn)
+	}
+}
+
+// authorize authorizes the call against the policy, logging the decision and
+// returning the context the call continues with
+func (i *Interceptor) authorize(ctx context.Context, procedure string, message proto.Message) (context.Context, error) {
+	claims, _ := auth.FromContext(ctx)
+	decision := i.policy.Authorize(procedure, claims, message)
+
+	fields := make([]zap.Field, 0, 4)
+	fields = append(fields,
+		zap.String("procedure", procedure),
+		zap.Bool("allowed", decision.Allowed),
+		zap.String("reason", decision.Reason),
+	)
+	if claims != nil {
+		fields = append(fields, zap.String("subject", claims.Subject))
+	}
+	logger := logging.FromContext(ctx).Named(LoggerName)
+	if !decision.Allowed {
+		logger.Warn("authorization decision", fields...)
+		return ctx, common_errors.PermissionDenied("permission denied").WithReason("POLICY_DENIED")
+	}
+	logger.Info("authorization decision", fields...)
+	if decision.Owner != "" {
+		ctx = authz.NewOwnerContext(ctx, decision.Owner)
+	}
+	return ctx, nil
+}
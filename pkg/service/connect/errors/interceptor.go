@@ -0,0 +1,162 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/common/logging"
+	"math"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// internalMessage is the message returned to callers for unexpected errors
+	internalMessage = "internal error"
+	// RetryAfterHeader is the header carrying the retry delay of errors, in
+	// whole seconds
+	RetryAfterHeader = "Retry-After"
+)
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// Interceptor is an error interceptor that converts domain errors returned by
+// handlers to connect errors. Unexpected errors are logged and returned as
+// CodeInternal without their details
+type Interceptor struct {
+	domain string
+}
+
+// NewInterceptor creates a new connect error interceptor. The domain is
+// reported in the google.rpc.ErrorInfo details, typically the service name
+func NewInterceptor(domain string) *Interceptor {
+	return &Interceptor{domain: domain}
+}
+
+// WrapUnary wraps a unary call converting any returned error
+func (i *Interceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		response, err := fn(ctx, request)
+		if err != nil {
+			return nil, i.convert(ctx, request.Spec(), err)
+		}
+		return response, nil
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *Interceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler wraps a streaming handler call converting any returned
+// error
+func (i *Interceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := fn(ctx, conn); err != nil {
+			return i.convert(ctx, conn.Spec(), err)
+		}
+		return nil
+	}
+}
+
+// convert converts the error to a connect error
+func (i *Interceptor) convert(ctx context.Context, spec connect.Spec, err error) error {
+
+	// Errors that are already connect errors were deliberately produced by a
+	// handler or another interceptor
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr
+	}
+
+	// Convert domain errors to the matching code, logging the cause if any
+	if domainErr, ok := common_errors.As(err); ok {
+		if cause := domainErr.Cause(); cause != nil {
+			logging.FromContext(ctx).Warn("request failed",
+				zap.String("grpc_method", spec.Procedure),
+				zap.Stringer("kind", domainErr.Kind),
+				zap.Error(cause),
+			)
+		}
+		return i.fromDomain(domainErr)
+	}
+
+	// Context errors are reported as is
+	switch {
+	case errors.Is(err, context.Canceled):
+		return connect.NewError(connect.CodeCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return connect.NewError(connect.CodeDeadlineExceeded, err)
+	}
+
+	// Anything else is unexpected, log it and hide it from the caller
+	logging.FromContext(ctx).Error("internal error", zap.String("grpc_method", spec.Procedure), zap.Error(err))
+	return connect.NewError(connect.CodeInternal, errors.New(internalMessage))
+}
+
+// fromDomain creates a connect error from the domain error, attaching the
+// ErrorInfo, BadRequest and RetryInfo details
+func (i *Interceptor) fromDomain(domainErr *common_errors.Error) *connect.Error {
+	connectErr := connect.NewError(Code(domainErr.Kind), errors.New(domainErr.Message))
+	i.addDetail(connectErr, &errdetails.ErrorInfo{
+		Reason:   domainErr.Reason,
+		Domain:   i.domain,
+		Metadata: domainErr.Metadata,
+	})
+	if len(domainErr.Violations) > 0 {
+		badRequest := &errdetails.BadRequest{
+			FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(domainErr.Violations)),
+		}
+		for _, violation := range domainErr.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Description,
+			})
+		}
+		i.addDetail(connectErr, badRequest)
+	}
+	if domainErr.RetryDelay > 0 {
+		i.addDetail(connectErr, &errdetails.RetryInfo{RetryDelay: durationpb.New(domainErr.RetryDelay)})
+		seconds := int64(math.Ceil(domainErr.RetryDelay.Seconds()))
+		connectErr.Meta().Set(RetryAfterHeader, strconv.FormatInt(seconds, 10))
+	}
+	return connectErr
+}
+
+// addDetail adds the detail to the error, details that cannot be encoded are
+// dropped
+func (i *Interceptor) addDetail(connectErr *connect.Error, msg proto.Message) {
+	if detail, err := connect.NewErrorDetail(msg); err == nil {
+		connectErr.AddDetail(detail)
+	}
+}
+
+// Code returns the connect code for the domain error kind
+func Code(kind common_errors.Kind) connect.Code {
+	switch kind {
+	case common_errors.KindNotFound:
+		return connect.CodeNotFound
+	case common_errors.KindAlreadyExists:
+		return connect.CodeAlreadyExists
+	case common_errors.KindInvalidArgument:
+		return connect.CodeInvalidArgument
+	case common_errors.KindPermissionDenied:
+		return connect.CodePermissionDenied
+	case common_errors.KindConflict:
+		return connect.CodeAborted
+	case common_errors.KindUnauthenticated:
+		return connect.CodeUnauthenticated
+	case common_errors.KindResourceExhausted:
+		return connect.CodeResourceExhausted
+	case common_errors.KindFailedPrecondition:
+		return connect.CodeFailedPrecondition
+	default:
+		return connect.CodeUnknown
+	}
+}
@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	common_errors "kitchen/pkg/common/errors"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+)
+
+const domain = "kitchen.test"
+
+// unaryError returns the error of a unary call failing with err through the
+// interceptor
+func unaryError(t *testing.T, err error) *connect.Error {
+	t.Helper()
+	call := NewInterceptor(domain).WrapUnary(func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, err
+	})
+	_, got := call(context.Background(), connect.NewRequest(&kitchenv1.GetPostRequest{Id: "1"}))
+	var connectErr *connect.Error
+	if !errors.As(got, &connectErr) {
+		t.Fatalf("error = %v, want a connect error", got)
+	}
+	return connectErr
+}
+
+// detail returns the detail of the error of the same type as want, failing
+// when there is none
+func detail[T proto.Message](t *testing.T, connectErr *connect.Error, want T) T {
+	t.Helper()
+	for _, d := range connectErr.Details() {
+		value, err := d.Value()
+		if err != nil {
+			t.Fatalf("failed to decode detail: %v", err)
+		}
+		if typed, ok := value.(T); ok {
+			return typed
+		}
+	}
+	t.Fatalf("error %v has no %T detail", connectErr, want)
+	return want
+}
+
+func TestConvertDomainErrors(t *testing.T) {
+	cause := errors.New("row 42 is missing")
+	connectErr := unaryError(t, fmt.Errorf("get post: %w", common_errors.NotFound("post", "1").WithCause(cause)))
+	if connectErr.Code() != connect.CodeNotFound {
+		t.Errorf("code = %v, want %v", connectErr.Code(), connect.CodeNotFound)
+	}
+
+	// Only the domain message is returned, never the internal cause
+	if connectErr.Message() != `post "1" not found` {
+		t.Errorf("message = %q, want the domain message", connectErr.Message())
+	}
+	info := detail(t, connectErr, &errdetails.ErrorInfo{})
+	if info.Reason != "NOT_FOUND" || info.Domain != domain || info.Metadata["id"] != "1" {
+		t.Errorf("error info = %v, want reason NOT_FOUND in domain %s for id 1", info, domain)
+	}
+}
+
+func TestConvertViolations(t *testing.T) {
+	connectErr := unaryError(t, common_errors.InvalidArgument("invalid post", common_errors.FieldViolation{
+		Field:       "post.caption",
+		Description: "must not be empty",
+	}))
+	if connectErr.Code() != connect.CodeInvalidArgument {
+		t.Errorf("code = %v, want %v", connectErr.Code(), connect.CodeInvalidArgument)
+	}
+	badRequest := detail(t, connectErr, &errdetails.BadRequest{})
+	if len(badRequest.FieldViolations) != 1 || badRequest.FieldViolations[0].Field != "post.caption" {
+		t.Errorf("bad request = %v, want a post.caption violation", badRequest)
+	}
+}
+
+func TestConvertRetryDelay(t *testing.T) {
+	connectErr := unaryError(t, common_errors.ResourceExhausted("rate limited").WithRetryDelay(1500*time.Millisecond))
+	if connectErr.Code() != connect.CodeResourceExhausted {
+		t.Errorf("code = %v, want %v", connectErr.Code(), connect.CodeResourceExhausted)
+	}
+
+	// The delay is rounded up to whole seconds in the header
+	if got := connectErr.Meta().Get(RetryAfterHeader); got != "2" {
+		t.Errorf("%s = %q, want 2", RetryAfterHeader, got)
+	}
+	retryInfo := detail(t, connectErr, &errdetails.RetryInfo{})
+	if got := retryInfo.RetryDelay.AsDuration(); got != 1500*time.Millisecond {
+		t.Errorf("retry delay = %v, want 1.5s", got)
+	}
+}
+
+func TestConvertOtherErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		err     error
+		code    connect.Code
+		message string
+	}{
+		{"connect", connect.NewError(connect.CodeUnavailable, errors.New("draining")), connect.CodeUnavailable, "draining"},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), connect.CodeCanceled, "query: context canceled"},
+		{"deadline", context.DeadlineExceeded, connect.CodeDeadlineExceeded, "context deadline exceeded"},
+		{"unexpected", errors.New("password=hunter2"), connect.CodeInternal, internalMessage},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			connectErr := unaryError(t, test.err)
+			if connectErr.Code() != test.code || connectErr.Message() != test.message {
+				t.Errorf("error = %v %q, want %v %q", connectErr.Code(), connectErr.Message(), test.code, test.message)
+			}
+		})
+	}
+}
+
+// streamingConn is a streaming handler connection with a spec only
+type streamingConn struct {
+	connect.StreamingHandlerConn
+}
+
+func (streamingConn) Spec() connect.Spec {
+	return connect.Spec{Procedure: "/kitchen.v1.KitchenService/WatchPosts", StreamType: connect.StreamTypeServer}
+}
+
+func TestConvertStreamingErrors(t *testing.T) {
+	call := NewInterceptor(domain).WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		return common_errors.PermissionDenied("not your post")
+	})
+	err := call(context.Background(), streamingConn{})
+	if code := connect.CodeOf(err); code != connect.CodePermissionDenied {
+		t.Errorf("code = %v, want %v", code, connect.CodePermissionDenied)
+	}
+
+	// Calls that succeed are untouched
+	call = NewInterceptor(domain).WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		return nil
+	})
+	if err := call(context.Background(), streamingConn{}); err != nil {
+		t.Errorf("successful call = %v, want nil", err)
+	}
+}
+
+func TestCode(t *testing.T) {
+	for kind, want := range map[common_errors.Kind]connect.Code{
+		common_errors.KindNotFound:           connect.CodeNotFound,
+		common_errors.KindAlreadyExists:      connect.CodeAlreadyExists,
+		common_errors.KindInvalidArgument:    connect.CodeInvalidArgument,
+		common_errors.KindPermissionDenied:   connect.CodePermissionDenied,
+		common_errors.KindConflict:           connect.CodeAborted,
+		common_errors.KindUnauthenticated:    connect.CodeUnauthenticated,
+		common_errors.KindResourceExhausted:  connect.CodeResourceExhausted,
+		common_errors.KindFailedPrecondition: connect.CodeFailedPrecondition,
+		common_errors.KindUnknown:            connect.CodeUnknown,
+	} {
+		if got := Code(kind); got != want {
+			t.Errorf("Code(%v) = %v, want %v", kind, got, want)
+		}
+	}
+}
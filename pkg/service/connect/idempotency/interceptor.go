@@ -0,0 +1,221 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/common/logging"
+	"kitchen/pkg/service/auth"
+	"kitchen/pkg/service/certs"
+	"kitchen/pkg/service/idempotency"
+	"kitchen/pkg/service/metadata"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// KeyHeader is the header carrying the idempotency key of a request
+	KeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed for a reused key
+	ReplayedHeader = "Idempotency-Replayed"
+	// maxKeyLength is the longest idempotency key accepted
+	maxKeyLength = 255
+	// claimTTL is how long a key stays claimed without being renewed, so a
+	// replica dying mid-request does not block retries for the full TTL. The
+	// claim is renewed every half claimTTL while the request is in flight
+	claimTTL = time.Minute
+)
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// Interceptor is an idempotency interceptor for unary calls carrying an
+// Idempotency-Key header. The first response for each key is stored per
+// caller and procedure for the TTL, and replayed to retries with the same key.
+// Reusing a key with a different request is rejected with a FailedPrecondition
+// domain error, and while the first request is in flight retries are rejected
+// with a Conflict domain error. Procedures without side effects are skipped, as
+// are anonymous callers, who have no scope of their own to store keys in
+type Interceptor struct {
+	store    idempotency.Store
+	ttl      time.Duration
+	claimTTL time.Duration
+}
+
+// NewInterceptor creates a new connect idempotency interceptor
+func NewInterceptor(store idempotency.Store, ttl time.Duration) *Interceptor {
+	return &Interceptor{store: store, ttl: ttl, claimTTL: claimTTL}
+}
+
+// WrapUnary wraps a unary call replaying the stored response for a reused
+// idempotency key
+func (i *Interceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		spec := request.Spec()
+		if spec.IsClient || spec.IdempotencyLevel == connect.IdempotencyNoSideEffects {
+			return fn(ctx, request)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		key := md.First(KeyHeader)
+		caller := caller(ctx)
+		if key == "" || caller == "" {
+			return fn(ctx, request)
+		}
+		if len(key) > maxKeyLength {
+			return nil, common_errors.InvalidArgument(fmt.Sprintf("idempotency key longer than %d characters", maxKeyLength)).
+				WithReason("INVALID_IDEMPOTENCY_KEY")
+		}
+		message, ok := request.Any().(proto.Message)
+		if !ok {
+			return fn(ctx, request)
+		}
+		hash, err := hashMessage(message)
+		if err != nil {
+			return nil, err
+		}
+
+		// Claim the key, or replay what the first request with it produced
+		storeKey := strings.Join([]string{spec.Procedure, caller, key}, " ")
+		record, err := i.store.Begin(ctx, storeKey, hash, i.claimTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if record != nil {
+			return replay(record, hash)
+		}
+
+		// Run the request holding the claim, then store its response or
+		// release the key so the request can be retried
+		stopRenewing := i.renew(ctx, storeKey)
+		response, err := fn(ctx, request)
+		stopRenewing()
+		if err != nil {
+			i.release(ctx, storeKey)
+			return nil, err
+		}
+		if err := i.complete(ctx, storeKey, hash, response); err != nil {
+			logging.FromContext(ctx).Warn("failed to store idempotent response", zap.Error(err))
+			i.release(ctx, storeKey)
+		}
+		return response, nil
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *Interceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler returns the handler call unchanged, streams are not
+// replayed
+func (i *Interceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return fn
+}
+
+// renew extends the claim on the key every half claim TTL until the returned
+// func is called, which waits for a renewal in progress
+func (i *Interceptor) renew(ctx context.Context, key string) func() {
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(i.claimTTL / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := i.store.Extend(context.WithoutCancel(ctx), key, i.claimTTL); err != nil {
+					logging.FromContext(ctx).Warn("failed to extend idempotency key claim", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// release releases the claim on the key, logging a failure
+func (i *Interceptor) release(ctx context.Context, key string) {
+	if err := i.store.Release(context.WithoutCancel(ctx), key); err != nil {
+		logging.FromContext(ctx).Warn("failed to release idempotency key", zap.Error(err))
+	}
+}
+
+// complete stores the response for the key
+func (i *Interceptor) complete(ctx context.Context, key string, hash []byte, response connect.AnyResponse) error {
+	message, ok := response.Any().(proto.Message)
+	if !ok {
+		return fmt.Errorf("response %T is not a proto message", response.Any())
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return i.store.Complete(ctx, key, idempotency.Record{
+		RequestHash:  hash,
+		ResponseType: string(message.ProtoReflect().Descriptor().FullName()),
+		Response:     data,
+	}, i.ttl)
+}
+
+// replayedMessage wraps a replayed response message, connect only needs the
+// message a handler responds with to be a proto message
+type replayedMessage struct {
+	proto.Message
+}
+
+// replay returns the response stored in the record, if it was stored for the
+// same request
+func replay(record *idempotency.Record, hash []byte) (connect.AnyResponse, error) {
+	if !bytes.Equal(record.RequestHash, hash) {
+		return nil, common_errors.FailedPrecondition("idempotency key was used with a different request").
+			WithReason("IDEMPOTENCY_KEY_REUSED")
+	}
+	if !record.Completed {
+		return nil, common_errors.Conflict("a request with the idempotency key is in progress").
+			WithReason("IDEMPOTENCY_KEY_IN_PROGRESS")
+	}
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(record.ResponseType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stored response type: %w", err)
+	}
+	message := messageType.New().Interface()
+	if err := proto.Unmarshal(record.Response, message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stored response: %w", err)
+	}
+	response := connect.NewResponse(&replayedMessage{message})
+	response.Header().Set(ReplayedHeader, "true")
+	return response, nil
+}
+
+// hashMessage hashes the deterministic encoding of the message
+func hashMessage(message proto.Message) ([]byte, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// caller returns the identity the keys of the caller are scoped to, empty for
+// anonymous callers
+func caller(ctx context.Context) string {
+	if claims, ok := auth.FromContext(ctx); ok && claims.Subject != "" {
+		return "user:" + claims.Subject
+	}
+	if peer, ok := certs.PeerFromContext(ctx); ok {
+		return "peer:" + peer.Name()
+	}
+	return ""
+}
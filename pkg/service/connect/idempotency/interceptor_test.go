@@ -0,0 +1,127 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/service/auth"
+	"kitchen/pkg/service/idempotency"
+	"kitchen/pkg/service/metadata"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestCallerScopes(t *testing.T) {
+	calls := 0
+	handler := NewInterceptor(idempotency.NewMemoryStore(), time.Hour).WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			calls++
+			return connect.NewResponse(&kitchenv1.CreatePostResponse{Id: fmt.Sprint(calls)}), nil
+		})
+	call := func(subject string) string {
+		t.Helper()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(KeyHeader, "key"))
+		if subject != "" {
+			ctx = auth.NewContext(ctx, &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: subject}})
+		}
+		response, err := handler(ctx, connect.NewRequest(&kitchenv1.CreatePostRequest{Caption: "hello"}))
+		if err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+		message := response.Any()
+		if replayed, ok := message.(*replayedMessage); ok {
+			message = replayed.Message
+		}
+		return message.(*kitchenv1.CreatePostResponse).Id
+	}
+
+	// Retries of a caller are replayed, but never to another caller
+	if first, retry := call("alice"), call("alice"); first != retry {
+		t.Errorf("retry by the same caller = %s, want the replayed %s", retry, first)
+	}
+	if got := call("bob"); got != "2" {
+		t.Errorf("call by another caller = %s, want a fresh response", got)
+	}
+
+	// Anonymous callers share no scope, their keys are ignored
+	if first, second := call(""), call(""); first == second {
+		t.Errorf("anonymous calls were replayed to each other")
+	}
+}
+
+// callerContext returns the context of a call by alice with the idempotency key
+func callerContext() context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(KeyHeader, "key"))
+	return auth.NewContext(ctx, &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "alice"}})
+}
+
+func TestClaimRenewedWhileInFlight(t *testing.T) {
+	interceptor := NewInterceptor(idempotency.NewMemoryStore(), time.Hour)
+	interceptor.claimTTL = 20 * time.Millisecond
+	started, finish := make(chan struct{}), make(chan struct{})
+	handler := interceptor.WrapUnary(func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+		select {
+		case <-started:
+		default:
+			close(started)
+			<-finish
+		}
+		return connect.NewResponse(&kitchenv1.CreatePostResponse{Id: "1"}), nil
+	})
+	request := connect.NewRequest(&kitchenv1.CreatePostRequest{Caption: "hello"})
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := handler(callerContext(), request)
+		first <- err
+	}()
+	<-started
+
+	// A retry long after the claim TTL still conflicts with the request in
+	// flight, whose claim is renewed
+	time.Sleep(10 * interceptor.claimTTL)
+	_, err := handler(callerContext(), request)
+	if kind := common_errors.KindOf(err); kind != common_errors.KindConflict {
+		t.Errorf("retry while in flight = %v, want a conflict", err)
+	}
+	close(finish)
+	if err := <-first; err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+}
+
+// failingStore is a memory store failing to complete keys
+type failingStore struct {
+	*idempotency.MemoryStore
+}
+
+func (failingStore) Complete(context.Context, string, idempotency.Record, time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func TestClaimReleasedWhenCompleteFails(t *testing.T) {
+	calls := 0
+	handler := NewInterceptor(failingStore{idempotency.NewMemoryStore()}, time.Hour).WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			calls++
+			return connect.NewResponse(&kitchenv1.CreatePostResponse{Id: fmt.Sprint(calls)}), nil
+		})
+	request := connect.NewRequest(&kitchenv1.CreatePostRequest{Caption: "hello"})
+
+	// A response that could not be stored does not leave the key claimed, so
+	// the retry runs again rather than conflicting
+	for want := 1; want <= 2; want++ {
+		if _, err := handler(callerContext(), request); err != nil {
+			t.Fatalf("call %d failed: %v", want, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
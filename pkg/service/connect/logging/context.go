@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"kitchen/pkg/common/logging"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"github.com/oklog/ulid/v2"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestIDHeader is the header carrying the request id. An id supplied by
+	// the caller is kept, otherwise one is generated, and it is echoed back on
+	// the response
+	RequestIDHeader = "X-Request-Id"
+	// LogFieldRequestID the logging field holding the request id
+	LogFieldRequestID = "request_id"
+	// maxRequestIDLength is the longest request id accepted from a caller
+	maxRequestIDLength = 128
+)
+
+var _ connect.Interceptor = (*ContextInterceptor)(nil)
+
+// ContextInterceptor is a logging interceptor that puts a request scoped
+// logger into the context, so logging.FromContext produces log lines carrying
+// the trace and span ids, the procedure and the request id. It must run inside
+// the tracing interceptor to see the request span
+type ContextInterceptor struct{}
+
+// NewContextInterceptor creates a new connect context logger interceptor
+func NewContextInterceptor() *ContextInterceptor {
+	return new(ContextInterceptor)
+}
+
+// WrapUnary wraps a unary call adding the request logger to the context
+func (i *ContextInterceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		if request.Spec().IsClient {
+			return fn(ctx, request)
+		}
+		requestID := requestID(request.Header().Get(RequestIDHeader))
+		response, err := fn(newContext(ctx, request.Spec(), requestID), request)
+		if response != nil {
+			response.Header().Set(RequestIDHeader, requestID)
+		}
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			connectErr.Meta().Set(RequestIDHeader, requestID)
+		}
+		return response, err
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *ContextInterceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler wraps a streaming handler call adding the request
+// logger to the context
+func (i *ContextInterceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		requestID := requestID(conn.RequestHeader().Get(RequestIDHeader))
+		conn.ResponseHeader().Set(RequestIDHeader, requestID)
+		return fn(newContext(ctx, conn.Spec(), requestID), conn)
+	}
+}
+
+// newContext returns a context holding a logger for the request
+func newContext(ctx context.Context, spec connect.Spec, requestID string) context.Context {
+	fields := make([]zap.Field, 0, 4)
+	fields = append(fields, zap.String("grpc_method", spec.Procedure), zap.String(LogFieldRequestID, requestID))
+
+	// Datadog correlates logs with traces by the low 64 bits of the ids,
+	// formatted as unsigned decimals
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		traceID, spanID := spanContext.TraceID(), spanContext.SpanID()
+		fields = append(fields,
+			zap.String(logging.LogFieldTraceID, strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10)),
+			zap.String(logging.LogFieldSpanID, strconv.FormatUint(binary.BigEndian.Uint64(spanID[:]), 10)),
+		)
+	}
+	return logging.NewContext(ctx, logging.FromContext(ctx).With(fields...))
+}
+
+// requestID returns the request id supplied by the caller, generating one when
+// it is missing or too long
+func requestID(supplied string) string {
+	if supplied != "" && len(supplied) <= maxRequestIDLength {
+		return supplied
+	}
+	return ulid.Make().String()
+}
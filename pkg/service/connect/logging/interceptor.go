@@ -0,0 +1,206 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"kitchen/pkg/common/logging"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxPayloadSize is the longest payload logged when no limit is set
+const DefaultMaxPayloadSize = 1024
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// Config is the access logging config
+type Config struct {
+	// SuccessLevel is the level successful calls are logged at, defaults to
+	// info
+	SuccessLevel zapcore.Level
+	// CodeToLevel returns the level a call failing with the code is logged
+	// at, defaults to DefaultCodeToLevel
+	CodeToLevel func(connect.Code) zapcore.Level
+	// Skip returns if calls to the procedure are not logged, defaults to
+	// logging every call
+	Skip func(procedure string) bool
+	// LogPayloads logs the request and response messages of unary calls as
+	// JSON. Payloads may contain personal data, only enable it when debugging
+	LogPayloads bool
+	// MaxPayloadSize is the longest payload logged in bytes, longer payloads
+	// are truncated. Defaults to DefaultMaxPayloadSize
+	MaxPayloadSize int
+}
+
+// Interceptor is an access logging interceptor that logs every call handled
+// with its procedure, peer, protocol, status code, duration and message sizes.
+// It logs with the request logger from the context, so it should run inside
+// the context interceptor
+type Interceptor struct {
+	cfg Config
+}
+
+// NewInterceptor creates a new connect access logging interceptor
+func NewInterceptor(cfg Config) *Interceptor {
+	if cfg.CodeToLevel == nil {
+		cfg.CodeToLevel = DefaultCodeToLevel
+	}
+	if cfg.Skip == nil {
+		cfg.Skip = func(string) bool { return false }
+	}
+	if cfg.MaxPayloadSize <= 0 {
+		cfg.MaxPayloadSize = DefaultMaxPayloadSize
+	}
+	return &Interceptor{cfg: cfg}
+}
+
+// DefaultCodeToLevel logs errors caused by the caller at info, errors worth
+// looking into at warn and server failures at error
+func DefaultCodeToLevel(code connect.Code) zapcore.Level {
+	switch code {
+	case connect.CodeCanceled, connect.CodeInvalidArgument, connect.CodeNotFound,
+		connect.CodeAlreadyExists, connect.CodeUnauthenticated:
+		return zapcore.InfoLevel
+	case connect.CodeDeadlineExceeded, connect.CodePermissionDenied, connect.CodeResourceExhausted,
+		connect.CodeFailedPrecondition, connect.CodeAborted, connect.CodeOutOfRange, connect.CodeUnavailable:
+		return zapcore.WarnLevel
+	}
+	return zapcore.ErrorLevel
+}
+
+// WrapUnary wraps a unary call logging it once it finishes
+func (i *Interceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		if request.Spec().IsClient || i.cfg.Skip(request.Spec().Procedure) {
+			return fn(ctx, request)
+		}
+		start := time.Now()
+		response, err := fn(ctx, request)
+
+		fields := i.callFields(request.Peer(), start)
+		fields = append(fields, zap.Int("request_size", size(request.Any())))
+		if i.cfg.LogPayloads {
+			fields = append(fields, zap.String("request", i.payload(request.Any())))
+		}
+		if response != nil {
+			fields = append(fields, zap.Int("response_size", size(response.Any())))
+			if i.cfg.LogPayloads {
+				fields = append(fields, zap.String("response", i.payload(response.Any())))
+			}
+		}
+		i.log(ctx, "unary call finished", err, fields)
+		return response, err
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *Interceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler wraps a streaming handler call logging it once it
+// finishes. Only the message counts and sizes are logged, never the payloads
+func (i *Interceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if i.cfg.Skip(conn.Spec().Procedure) {
+			return fn(ctx, conn)
+		}
+		start := time.Now()
+		counted := &streamingHandlerConn{StreamingHandlerConn: conn}
+		err := fn(ctx, counted)
+
+		fields := i.callFields(conn.Peer(), start)
+		fields = append(fields,
+			zap.Int64("request_messages", counted.received.Load()),
+			zap.Int64("request_size", counted.receivedSize.Load()),
+			zap.Int64("response_messages", counted.sent.Load()),
+			zap.Int64("response_size", counted.sentSize.Load()),
+		)
+		i.log(ctx, "streaming call finished", err, fields)
+		return err
+	}
+}
+
+// callFields returns the fields common to every call
+func (i *Interceptor) callFields(peer connect.Peer, start time.Time) []zap.Field {
+	fields := make([]zap.Field, 0, 10)
+	return append(fields,
+		zap.String("peer", peer.Addr),
+		zap.String("protocol", peer.Protocol),
+		zap.Duration("duration", time.Since(start)),
+	)
+}
+
+// log logs the finished call at the level of its status code
+func (i *Interceptor) log(ctx context.Context, msg string, err error, fields []zap.Field) {
+	code, level := "ok", i.cfg.SuccessLevel
+	if err != nil {
+		connectCode := connect.CodeOf(err)
+		code, level = connectCode.String(), i.cfg.CodeToLevel(connectCode)
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			fields = append(fields, zap.String("grpc_message", connectErr.Message()))
+		}
+	}
+	fields = append(fields, zap.String("grpc_code", code))
+	if entry := logging.FromContext(ctx).Check(level, msg); entry != nil {
+		entry.Write(fields...)
+	}
+}
+
+// payload renders the message as JSON, truncated to the maximum payload size
+func (i *Interceptor) payload(msg any) string {
+	message, ok := msg.(proto.Message)
+	if !ok {
+		return ""
+	}
+	b, err := protojson.Marshal(message)
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	if len(b) > i.cfg.MaxPayloadSize {
+		return string(b[:i.cfg.MaxPayloadSize]) + "...<truncated>"
+	}
+	return string(b)
+}
+
+// size returns the encoded size of the message
+func size(msg any) int {
+	if message, ok := msg.(proto.Message); ok {
+		return proto.Size(message)
+	}
+	return 0
+}
+
+// streamingHandlerConn counts the messages sent and received on the stream
+type streamingHandlerConn struct {
+	connect.StreamingHandlerConn
+	received, receivedSize atomic.Int64
+	sent, sentSize         atomic.Int64
+}
+
+// Receive receives and counts the next message
+func (c *streamingHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	c.received.Add(1)
+	c.receivedSize.Add(int64(size(msg)))
+	return nil
+}
+
+// Send sends and counts the message
+func (c *streamingHandlerConn) Send(msg any) error {
+	if err := c.StreamingHandlerConn.Send(msg); err != nil {
+		return err
+	}
+	c.sent.Add(1)
+	c.sentSize.Add(int64(size(msg)))
+	return nil
+}
@@ -2,8 +2,12 @@ package connect
 
 import (
 	"kitchen/pkg/service/cors"
+	"kitchen/pkg/service/idempotency"
+	"kitchen/pkg/service/ratelimit"
 
 	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap/zapcore"
 )
 
 // Option is a configuration option
@@ -14,6 +18,13 @@ type options struct {
 	additionalServices []ServiceRegistrar
 	handlerOptions     []connect.HandlerOption
 	corsOptions        []cors.Option
+	meterProvider      metric.MeterProvider
+	codeToLevel        func(connect.Code) zapcore.Level
+	// unauthenticatedProcedures are let through without a token, in addition
+	// to the health and reflection services
+	unauthenticatedProcedures []string
+	rateLimiter               ratelimit.Limiter
+	idempotencyStore          idempotency.Store
 }
 
 // WithAdditionalService specifies an additional service to mount on the connect
@@ -31,9 +42,50 @@ func WithHandlerOptions(handlerOptions ...connect.HandlerOption) Option {
 	}
 }
 
-// WithCORSOptions passes the set of cors options to use
+// WithCORSOptions passes cors options to apply on top of the policy from the
+// config
 func WithCORSOptions(corsOptions ...cors.Option) Option {
 	return func(options *options) {
-		options.corsOptions = corsOptions
+		options.corsOptions = append(options.corsOptions, corsOptions...)
+	}
+}
+
+// WithMeterProvider specifies the meter provider the RPC metrics are recorded
+// with, defaults to the global meter provider
+func WithMeterProvider(meterProvider metric.MeterProvider) Option {
+	return func(options *options) {
+		options.meterProvider = meterProvider
+	}
+}
+
+// WithAccessLogLevels specifies the level failed calls are logged at in the
+// access log by their code, defaults to connect_logging.DefaultCodeToLevel
+func WithAccessLogLevels(codeToLevel func(connect.Code) zapcore.Level) Option {
+	return func(options *options) {
+		options.codeToLevel = codeToLevel
+	}
+}
+
+// WithUnauthenticatedProcedures specifies procedures callers may call without
+// a bearer token, such as /kitchen.v1.KitchenService/GetPost
+func WithUnauthenticatedProcedures(procedures ...string) Option {
+	return func(options *options) {
+		options.unauthenticatedProcedures = append(options.unauthenticatedProcedures, procedures...)
+	}
+}
+
+// WithRateLimiter specifies the limiter holding the rate limit buckets,
+// defaults to an in-memory limiter per server
+func WithRateLimiter(limiter ratelimit.Limiter) Option {
+	return func(options *options) {
+		options.rateLimiter = limiter
+	}
+}
+
+// WithIdempotencyStore specifies the store holding the responses to requests
+// with an Idempotency-Key, defaults to an in-memory store per server
+func WithIdempotencyStore(store idempotency.Store) Option {
+	return func(options *options) {
+		options.idempotencyStore = store
 	}
 }
@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/common/logging"
+	"kitchen/pkg/service/auth"
+	"kitchen/pkg/service/certs"
+	"kitchen/pkg/service/metadata"
+	"kitchen/pkg/service/ratelimit"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+)
+
+const (
+	// APIKeyHeader is the header carrying the API key of the caller
+	APIKeyHeader = "X-Api-Key"
+	// forwardedForHeader carries the client IP and the proxies a call passed
+	// through when the service is behind a load balancer
+	forwardedForHeader = "X-Forwarded-For"
+)
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// Interceptor is a rate limiting interceptor that takes a token from the
+// bucket of the caller for every call to a procedure with a rate limit. Calls
+// finding the bucket empty are rejected with a ResourceExhausted domain error
+// carrying the retry delay, which the error interceptor converts to
+// CodeResourceExhausted with a Retry-After header. It must run inside the
+// metadata and authentication interceptors to identify callers
+type Interceptor struct {
+	limiter        ratelimit.Limiter
+	rules          map[string]ratelimit.Rule
+	trustedProxies []netip.Prefix
+}
+
+// NewInterceptor creates a new connect rate limiting interceptor enforcing the
+// rules, which must be valid. The X-Forwarded-For header is only read from
+// connections of the trusted proxies
+func NewInterceptor(limiter ratelimit.Limiter, rules []ratelimit.Rule, trustedProxies []netip.Prefix) *Interceptor {
+	i := &Interceptor{
+		limiter:        limiter,
+		rules:          make(map[string]ratelimit.Rule, len(rules)),
+		trustedProxies: trustedProxies,
+	}
+	for _, rule := range rules {
+		i.rules[rule.Procedure] = rule
+	}
+	return i
+}
+
+// WrapUnary wraps a unary call limiting the rate of the caller
+func (i *Interceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		if request.Spec().IsClient {
+			return fn(ctx, request)
+		}
+		if err := i.take(ctx, request.Spec().Procedure, request.Peer()); err != nil {
+			return nil, err
+		}
+		return fn(ctx, request)
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *Interceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler wraps a streaming handler call limiting the rate the
+// caller opens streams at
+func (i *Interceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.take(ctx, conn.Spec().Procedure, conn.Peer()); err != nil {
+			return err
+		}
+		return fn(ctx, conn)
+	}
+}
+
+// take takes a token from the bucket of the caller, if the procedure has a
+// rate limit. Calls are let through when the limiter fails
+func (i *Interceptor) take(ctx context.Context, procedure string, peer connect.Peer) error {
+	rule, ok := i.rules[procedure]
+	if !ok {
+		if rule, ok = i.rules[ratelimit.AnyProcedure]; !ok {
+			return nil
+		}
+	}
+	caller := i.callerKey(ctx, rule.KeyBy, peer)
+	if caller == "" {
+		return nil
+	}
+	allowed, wait, err := i.limiter.Take(ctx, procedure+" "+caller, rule.Limit())
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to take from rate limit bucket", zap.Error(err))
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+	return common_errors.ResourceExhausted("rate limit exceeded").
+		WithReason("RATE_LIMITED").
+		WithRetryDelay(wait)
+}
+
+// callerKey returns the key identifying the caller by the rule's key_by, empty
+// when the caller cannot be identified that way
+func (i *Interceptor) callerKey(ctx context.Context, keyBy string, peer connect.Peer) string {
+	switch keyBy {
+	case ratelimit.KeyByUser:
+		return userKey(ctx)
+	case ratelimit.KeyByAPIKey:
+		if key := apiKey(ctx); key != "" {
+			return key
+		}
+		return i.ipKey(ctx, peer)
+	case ratelimit.KeyByIP:
+		return i.ipKey(ctx, peer)
+	}
+	if key := userKey(ctx); key != "" {
+		return key
+	}
+	return i.ipKey(ctx, peer)
+}
+
+// userKey returns the key of the authenticated user or service
+func userKey(ctx context.Context) string {
+	if claims, ok := auth.FromContext(ctx); ok && claims.Subject != "" {
+		return "user:" + claims.Subject
+	}
+	if peer, ok := certs.PeerFromContext(ctx); ok {
+		return "peer:" + peer.Name()
+	}
+	return ""
+}
+
+// apiKey returns the key of the caller's API key, hashed so shared limiters
+// never store the secret
+func apiKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if key := md.First(APIKeyHeader); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "api_key:" + hex.EncodeToString(sum[:16])
+	}
+	return ""
+}
+
+// ipKey returns the key of the client IP. It is the address of the
+// connection, unless that is a trusted proxy, then it is the rightmost address
+// in X-Forwarded-For that is not a trusted proxy. Addresses to the left of it
+// were set by the client, and cannot be trusted
+func (i *Interceptor) ipKey(ctx context.Context, peer connect.Peer) string {
+	addr, ok := parseAddr(peer.Addr)
+	if !ok {
+		return ""
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	hops := strings.Split(strings.Join(md.Get(forwardedForHeader), ","), ",")
+	for len(hops) > 0 && i.trusted(addr) {
+		hop, ok := parseAddr(strings.TrimSpace(hops[len(hops)-1]))
+		hops = hops[:len(hops)-1]
+		if !ok {
+			break
+		}
+		addr = hop
+	}
+	return "ip:" + addr.String()
+}
+
+// trusted returns if the address is one of a trusted proxy
+func (i *Interceptor) trusted(addr netip.Addr) bool {
+	return slices.ContainsFunc(i.trustedProxies, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// parseAddr parses an IP, with or without a port
+func parseAddr(s string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
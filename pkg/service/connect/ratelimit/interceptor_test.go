@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	common_errors "kitchen/pkg/common/errors"
+	"kitchen/pkg/service/metadata"
+	"kitchen/pkg/service/ratelimit"
+
+	"connectrpc.com/connect"
+)
+
+func TestIPKey(t *testing.T) {
+	trustedProxies, err := ratelimit.ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies failed: %v", err)
+	}
+	i := NewInterceptor(ratelimit.NewMemoryLimiter(0), nil, trustedProxies)
+
+	for _, test := range []struct {
+		name      string
+		addr      string
+		forwarded []string
+		want      string
+	}{
+		{"direct", "203.0.113.7:1234", nil, "ip:203.0.113.7"},
+		{"direct ignores forwarded", "203.0.113.7:1234", []string{"198.51.100.1"}, "ip:203.0.113.7"},
+		{"proxied", "10.0.0.1:1234", []string{"198.51.100.1"}, "ip:198.51.100.1"},
+		{"spoofed hop", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1"}, "ip:198.51.100.1"},
+		{"proxy chain", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1, 192.168.1.1"}, "ip:198.51.100.1"},
+		{"header lines", "10.0.0.1:1234", []string{"1.2.3.4", "198.51.100.1, 10.1.1.1"}, "ip:198.51.100.1"},
+		{"garbage hop", "10.0.0.1:1234", []string{"1.2.3.4, garbage"}, "ip:10.0.0.1"},
+		{"proxied without header", "10.0.0.1:1234", nil, "ip:10.0.0.1"},
+		{"mapped ipv4", "[::ffff:203.0.113.7]:1234", nil, "ip:203.0.113.7"},
+		{"no address", "", nil, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if test.forwarded != nil {
+				md.Set(forwardedForHeader, test.forwarded...)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			if got := i.ipKey(ctx, connect.Peer{Addr: test.addr}); got != test.want {
+				t.Errorf("ipKey = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCallerKeyAPIKey(t *testing.T) {
+	i := NewInterceptor(ratelimit.NewMemoryLimiter(0), nil, nil)
+	peer := connect.Peer{Addr: "203.0.113.7:1234"}
+	withKey := metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyHeader, "secret"))
+	withoutKey := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+
+	// Rules keyed by API key use the hashed key, and the client IP without one
+	key := i.callerKey(withKey, ratelimit.KeyByAPIKey, peer)
+	if !strings.HasPrefix(key, "api_key:") || strings.Contains(key, "secret") {
+		t.Errorf("api key = %q, want a hashed api_key key", key)
+	}
+	if got := i.callerKey(withoutKey, ratelimit.KeyByAPIKey, peer); got != "ip:203.0.113.7" {
+		t.Errorf("api key without a key = %q, want the client IP", got)
+	}
+
+	// Other rules ignore the key, so rotating it cannot reset the bucket
+	for _, keyBy := range []string{"", ratelimit.KeyByCaller, ratelimit.KeyByIP} {
+		if got := i.callerKey(withKey, keyBy, peer); got != "ip:203.0.113.7" {
+			t.Errorf("%q key with an api key = %q, want the client IP", keyBy, got)
+		}
+	}
+}
+
+func TestTakeAPIKey(t *testing.T) {
+	i := NewInterceptor(ratelimit.NewMemoryLimiter(0), []ratelimit.Rule{{
+		Procedure: ratelimit.AnyProcedure,
+		Requests:  1,
+		Period:    time.Hour,
+		KeyBy:     ratelimit.KeyByAPIKey,
+	}}, nil)
+	peer := connect.Peer{Addr: "203.0.113.7:1234"}
+	take := func(key string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyHeader, key))
+		return i.take(ctx, "/kitchen.v1.KitchenService/CreatePost", peer)
+	}
+
+	if err := take("one"); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	if err := take("one"); common_errors.KindOf(err) != common_errors.KindResourceExhausted {
+		t.Errorf("second call with the key = %v, want ResourceExhausted", err)
+	}
+	if err := take("two"); err != nil {
+		t.Errorf("call with another key failed: %v", err)
+	}
+}
@@ -2,15 +2,31 @@ package connect
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"kitchen/pkg/common/config"
 	"kitchen/pkg/common/logging"
 	"kitchen/pkg/service"
+	"kitchen/pkg/service/auth"
+	"kitchen/pkg/service/authz"
+	"kitchen/pkg/service/certs"
 	"kitchen/pkg/service/cors"
+	"kitchen/pkg/service/health"
+	"kitchen/pkg/service/idempotency"
+	"kitchen/pkg/service/ratelimit"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
 
+	connect_auth "kitchen/pkg/service/connect/auth"
+	connect_authz "kitchen/pkg/service/connect/authz"
+	connect_errors "kitchen/pkg/service/connect/errors"
+	connect_idempotency "kitchen/pkg/service/connect/idempotency"
+	connect_logging "kitchen/pkg/service/connect/logging"
 	connect_metadata "kitchen/pkg/service/connect/metadata"
+	connect_ratelimit "kitchen/pkg/service/connect/ratelimit"
+	connect_validate "kitchen/pkg/service/connect/validate"
 
 	"connectrpc.com/connect"
 	"connectrpc.com/grpcreflect"
@@ -36,6 +52,16 @@ type Server struct {
 	preShutdownHooks []service.PreShutdownHook
 	shutdownHooks    []service.ShutdownHook
 	cfg              service.Config
+	health           *health.Checker
+	otelOptions      []otelconnect.Option
+	accessLogConfig  connect_logging.Config
+	authInterceptor  *connect_auth.Interceptor
+	authzInterceptor *connect_authz.Interceptor
+	rateLimiter      *connect_ratelimit.Interceptor
+	validator        *connect_validate.Interceptor
+	idempotency      *connect_idempotency.Interceptor
+	certs            *certs.Reloader
+	listening        chan struct{}
 }
 
 // ServiceRegistrar provides the ability to register services to a server
@@ -59,18 +85,40 @@ func (s Service[T]) Register(server *Server, opts ...connect.HandlerOption) stri
 // NewServer creates a new connect server
 func NewServer[T any](cfg service.Config, factory HandlerFactory[T], handler T, opts ...Option) *Server {
 
-	// Parse the configuration options
+	// Parse the configuration options, the CORS policy comes from the config
+	// unless overridden
 	var options options
+	options.corsOptions = cfg.CORS.Options(cfg.Env)
 	for _, opt := range opts {
 		opt(&options)
 	}
 
 	// Create a Server instance
 	s := &Server{
-		cfg:    cfg,
-		logger: logging.NewLogger(LoggerName),
-		mux:    http.NewServeMux(),
+		cfg:       cfg,
+		logger:    logging.NewLogger(LoggerName),
+		mux:       http.NewServeMux(),
+		listening: make(chan struct{}),
 	}
+	s.otelOptions = append(s.otelOptions, otelconnect.WithTrustRemote(), otelconnect.WithoutServerPeerAttributes())
+	if options.meterProvider != nil {
+		s.otelOptions = append(s.otelOptions, otelconnect.WithMeterProvider(options.meterProvider))
+	}
+	s.accessLogConfig = connect_logging.Config{
+		CodeToLevel:    options.codeToLevel,
+		LogPayloads:    cfg.LogPayloads,
+		MaxPayloadSize: cfg.LogPayloadMaxSize,
+	}
+	s.configureAuth(options)
+	s.configureAuthz()
+	s.configureRateLimits(options)
+	s.configureValidation()
+	store := options.idempotencyStore
+	if store == nil {
+		store = idempotency.NewMemoryStore()
+	}
+	s.idempotency = connect_idempotency.NewInterceptor(store, cfg.IdempotencyKeyTTL)
+
 	// Register all the services
 	serviceNames := make([]string, 0, 1+len(options.additionalServices))
 	services := make([]ServiceRegistrar, 0, 1+len(options.additionalServices))
@@ -91,18 +139,23 @@ func NewServer[T any](cfg service.Config, factory HandlerFactory[T], handler T,
 	s.mux.Handle(grpcreflect.NewHandlerV1(reflector))
 	s.mux.Handle(grpcreflect.NewHandlerV1Alpha(reflector))
 
+	// Register the health service, reporting on every mounted service
+	s.health = health.NewChecker(serviceNames...)
+	s.mux.Handle(health.NewHandler(s.health))
+
 	// Register our hooks
 	if hook, ok := any(handler).(interface{ PreStart(context.Context) error }); ok {
 		s.RegisterPreStartHook(hook.PreStart)
 	}
-	if hook, ok := any(handler).(interface{ PreShutdown() error }); ok {
+	if hook, ok := any(handler).(interface{ PreShutdown(context.Context) error }); ok {
 		s.RegisterPreShutdownHook(hook.PreShutdown)
 	}
-	if hook, ok := any(handler).(interface{ Shutdown() error }); ok {
+	if hook, ok := any(handler).(interface{ Shutdown(context.Context) error }); ok {
 		s.RegisterShutdownHook(hook.Shutdown)
 	}
 
-	// Create the http server
+	// Create the http server, serving TLS when a certificate is configured and
+	// h2c plaintext otherwise
 	s.httpServer = &http.Server{
 		Addr:              fmt.Sprintf("%s:%d", cfg.BindAddress, cfg.GrpcPort),
 		Handler:           h2c.NewHandler(cors.New(options.corsOptions...).Handler(s.mux), &http2.Server{}),
@@ -110,10 +163,17 @@ func NewServer[T any](cfg service.Config, factory HandlerFactory[T], handler T,
 		ReadTimeout:       cfg.ReadTimeout,
 		WriteTimeout:      cfg.WriteTimeout,
 	}
+	s.configureTLS(options)
 
 	return s
 }
 
+// Health returns the checker backing the grpc.health.v1 service, register
+// dependency checks with it
+func (s *Server) Health() *health.Checker {
+	return s.health
+}
+
 // RegisterPreStartHook registers a pre-start hook
 func (s *Server) RegisterPreStartHook(hooks ...service.PreStartHook) {
 	s.preStartHooks = append(s.preStartHooks, hooks...)
@@ -129,14 +189,152 @@ func (s *Server) RegisterShutdownHook(hooks ...service.ShutdownHook) {
 	s.shutdownHooks = append(s.shutdownHooks, hooks...)
 }
 
+// configureAuth creates the authentication interceptor when token issuers or
+// a client CA are configured. Without issuers only client certificates
+// authenticate callers. A verifier that cannot be created fails the server at
+// start, rather than serving unauthenticated
+func (s *Server) configureAuth(options options) {
+	if len(s.cfg.ValidIssuers) == 0 && s.cfg.TLSClientCAFile == "" {
+		if s.cfg.Env != config.Local {
+			s.logger.Warn("no valid issuers or client ca configured, authentication is disabled")
+		}
+		return
+	}
+	var verifier connect_auth.Verifier
+	if len(s.cfg.ValidIssuers) > 0 {
+		tokenVerifier, err := auth.NewVerifier(auth.Config{
+			Issuers:         s.cfg.ValidIssuers,
+			KeySetURLs:      s.cfg.JWKSURLs,
+			Audience:        s.cfg.JWTAudience,
+			RefreshInterval: s.cfg.JWKSRefreshInterval,
+		})
+		if err != nil {
+			s.logger.Error("failed to create token verifier", zap.Error(err))
+			s.RegisterPreStartHook(func(context.Context) error {
+				return fmt.Errorf("failed to create token verifier: %w", err)
+			})
+			return
+		}
+		verifier = tokenVerifier
+		s.RegisterPreStartHook(tokenVerifier.Start)
+		s.RegisterShutdownHook(tokenVerifier.Stop)
+	}
+	exempt := connect_auth.DefaultExempt
+	if len(options.unauthenticatedProcedures) > 0 {
+		exempt = func(procedure string) bool {
+			return connect_auth.DefaultExempt(procedure) || slices.Contains(options.unauthenticatedProcedures, procedure)
+		}
+	}
+	s.authInterceptor = connect_auth.NewInterceptor(verifier, exempt)
+}
+
+// configureTLS serves TLS from certificates reloaded from disk when they are
+// configured, putting the identity of callers presenting a client certificate
+// into the context. A reloader that cannot be created fails the server at
+// start, rather than serving plaintext
+func (s *Server) configureTLS(options options) {
+	if s.cfg.TLSCertFile == "" {
+		return
+	}
+	reloader, err := certs.NewReloader(certs.Config{
+		CertFile:          s.cfg.TLSCertFile,
+		KeyFile:           s.cfg.TLSKeyFile,
+		ClientCAFile:      s.cfg.TLSClientCAFile,
+		RequireClientCert: s.cfg.TLSRequireClientCert,
+		ReloadInterval:    s.cfg.TLSReloadInterval,
+	})
+	if err != nil {
+		s.logger.Error("failed to create certificate reloader", zap.Error(err))
+		s.RegisterPreStartHook(func(context.Context) error {
+			return fmt.Errorf("failed to create certificate reloader: %w", err)
+		})
+		return
+	}
+	s.certs = reloader
+	s.httpServer.Handler = cors.New(options.corsOptions...).Handler(certs.Middleware(s.mux))
+	s.httpServer.TLSConfig = reloader.TLSConfig()
+	s.RegisterPreStartHook(reloader.Start)
+	s.RegisterShutdownHook(reloader.Stop)
+}
+
+// configureAuthz creates the authorization interceptor when rules are
+// configured. A policy that cannot be created fails the server at start,
+// rather than serving unauthorized
+func (s *Server) configureAuthz() {
+	if len(s.cfg.AuthzRules) == 0 && !s.cfg.AuthzDefaultDeny {
+		return
+	}
+	policy, err := authz.NewPolicy(s.cfg.AuthzRules, s.cfg.AuthzDefaultDeny)
+	if err != nil {
+		s.logger.Error("failed to create authorization policy", zap.Error(err))
+		s.RegisterPreStartHook(func(context.Context) error {
+			return fmt.Errorf("failed to create authorization policy: %w", err)
+		})
+		return
+	}
+	s.authzInterceptor = connect_authz.NewInterceptor(policy)
+}
+
+// configureRateLimits creates the rate limiting interceptor when rate limits
+// are configured. Trusted proxies that cannot be parsed fail the server at
+// start, rather than limiting every client behind them together
+func (s *Server) configureRateLimits(options options) {
+	if len(s.cfg.RateLimits) == 0 {
+		return
+	}
+	trustedProxies, err := ratelimit.ParseTrustedProxies(s.cfg.RateLimitTrustedProxies)
+	if err != nil {
+		s.logger.Error("failed to parse trusted proxies", zap.Error(err))
+		s.RegisterPreStartHook(func(context.Context) error {
+			return fmt.Errorf("failed to parse trusted proxies: %w", err)
+		})
+		return
+	}
+	limiter := options.rateLimiter
+	if limiter == nil {
+		limiter = ratelimit.NewMemoryLimiter(ratelimit.DefaultMaxBuckets)
+	}
+	s.rateLimiter = connect_ratelimit.NewInterceptor(limiter, s.cfg.RateLimits, trustedProxies)
+}
+
+// configureValidation creates the validation interceptor. A validator that
+// cannot be created fails the server at start, rather than serving requests
+// unvalidated
+func (s *Server) configureValidation() {
+	validator, err := connect_validate.NewInterceptor()
+	if err != nil {
+		s.logger.Error("failed to create validation interceptor", zap.Error(err))
+		s.RegisterPreStartHook(func(context.Context) error {
+			return fmt.Errorf("failed to create validation interceptor: %w", err)
+		})
+		return
+	}
+	s.validator = validator
+}
+
 // defaultOptions creates the set of default options
 func (s *Server) defaultOptions(opts []connect.HandlerOption) []connect.HandlerOption {
-	interceptors := make([]connect.Interceptor, 0, 4)
-	if interceptor, err := otelconnect.NewInterceptor(otelconnect.WithTrustRemote()); err == nil {
+	interceptors := make([]connect.Interceptor, 0, 11)
+	if interceptor, err := otelconnect.NewInterceptor(s.otelOptions...); err == nil {
 		interceptors = append(interceptors, interceptor)
 	}
-	// interceptors = append(interceptors, connect_logging.NewInterceptor(connect_logging.Config{}))
+	interceptors = append(interceptors, connect_logging.NewContextInterceptor())
+	interceptors = append(interceptors, connect_logging.NewInterceptor(s.accessLogConfig))
 	interceptors = append(interceptors, connect_metadata.NewInterceptor())
+	interceptors = append(interceptors, connect_errors.NewInterceptor(s.cfg.ServiceName))
+	if s.authInterceptor != nil {
+		interceptors = append(interceptors, s.authInterceptor)
+	}
+	if s.rateLimiter != nil {
+		interceptors = append(interceptors, s.rateLimiter)
+	}
+	if s.authzInterceptor != nil {
+		interceptors = append(interceptors, s.authzInterceptor)
+	}
+	if s.validator != nil {
+		interceptors = append(interceptors, s.validator)
+	}
+	interceptors = append(interceptors, s.idempotency)
 	defaults := make([]connect.HandlerOption, 0, len(opts)+2)
 	defaults = append(defaults, connect.WithInterceptors(interceptors...), connect.WithRecover(s.recover))
 	return append(defaults, opts...)
@@ -159,27 +357,46 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 
 	s.logger.Info("connect service listening on port", zap.String("addr", s.cfg.BindAddress), zap.Int("port", s.cfg.GrpcPort))
+	close(s.listening)
 
 	// Serve our traffic
+	if s.certs != nil {
+		return s.httpServer.ServeTLS(lis, "", "")
+	}
 	return s.httpServer.Serve(lis)
 }
 
-// Stop stops this server
-func (s *Server) Stop() error {
+// Listening returns a channel that is closed once the server is accepting
+// connections
+func (s *Server) Listening() <-chan struct{} {
+	return s.listening
+}
+
+// Stop stops this server. In-flight requests are given until the context
+// deadline to complete, after which any remaining connections are closed
+func (s *Server) Stop(ctx context.Context) error {
 
 	s.logger.Info("stopping connect service")
 
 	// Sync the log when complete
 	defer s.logger.Sync()
 
+	// Report every service as not serving from now on
+	s.health.Shutdown()
+
 	// Run the registered pre-shutdown hooks
-	s.runPreShutdownHooks()
+	s.runPreShutdownHooks(ctx)
 
-	// Stop the underlying connect server
-	err := s.httpServer.Shutdown(context.Background())
+	// Stop the underlying connect server, forcing it closed if the deadline
+	// passes first
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		s.logger.Warn("graceful shutdown failed, closing connections", zap.Error(err))
+		err = errors.Join(err, s.httpServer.Close())
+	}
 
 	// Run the registered shutdown hooks
-	s.runShutdownHooks()
+	s.runShutdownHooks(ctx)
 
 	// Flush the Logger
 	return err
@@ -201,27 +418,27 @@ func (s *Server) runPreStartHooks(ctx context.Context) error {
 }
 
 // runPreShutdownHooks runs any registered pre-shutdown hooks
-func (s *Server) runPreShutdownHooks() {
+func (s *Server) runPreShutdownHooks(ctx context.Context) {
 	if len(s.preShutdownHooks) == 0 {
 		return
 	}
 	s.logger.Info("running pre-shutdown hooks")
 	for i, hook := range s.preShutdownHooks {
-		if err := hook(); err != nil {
-			s.logger.Error("pre-shutdown hook failed", zap.Int("index", i))
+		if err := hook(ctx); err != nil {
+			s.logger.Error("pre-shutdown hook failed", zap.Int("index", i), zap.Error(err))
 		}
 	}
 }
 
 // runShutdownHooks runs any registered shutdown hooks
-func (s *Server) runShutdownHooks() {
+func (s *Server) runShutdownHooks(ctx context.Context) {
 	if len(s.shutdownHooks) == 0 {
 		return
 	}
 	s.logger.Info("running shutdown hooks")
 	for i, hook := range s.shutdownHooks {
-		if err := hook(); err != nil {
-			s.logger.Error("shutdown hook failed", zap.Int("index", i))
+		if err := hook(ctx); err != nil {
+			s.logger.Error("shutdown hook failed", zap.Int("index", i), zap.Error(err))
 		}
 	}
 }
@@ -0,0 +1,49 @@
+package connect
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"kitchen/pkg/service"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+	"kitchen/proto/gen/kitchen/v1/kitchenv1connect"
+
+	"connectrpc.com/connect"
+)
+
+func TestConfigureAuth(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		cfg  service.Config
+		want bool
+	}{
+		{"disabled", service.Config{}, false},
+		{"tokens", service.Config{ValidIssuers: []string{"https://issuer.example.com"}}, true},
+		{"client certificates only", service.Config{TLSClientCAFile: "ca.pem"}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewServer(test.cfg, kitchenv1connect.NewKitchenServiceHandler, kitchenv1connect.KitchenServiceHandler(kitchenv1connect.UnimplementedKitchenServiceHandler{}))
+			if got := s.authInterceptor != nil; got != test.want {
+				t.Errorf("authentication enabled = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	s := NewServer(service.Config{}, kitchenv1connect.NewKitchenServiceHandler, kitchenv1connect.KitchenServiceHandler(kitchenv1connect.UnimplementedKitchenServiceHandler{}))
+	server := httptest.NewServer(s.mux)
+	t.Cleanup(server.Close)
+	client := kitchenv1connect.NewKitchenServiceClient(server.Client(), server.URL)
+
+	// Invalid requests are rejected before they reach the handler
+	_, err := client.GetPost(context.Background(), connect.NewRequest(&kitchenv1.GetPostRequest{}))
+	if code := connect.CodeOf(err); code != connect.CodeInvalidArgument {
+		t.Errorf("GetPost without an id = %v, want %v", code, connect.CodeInvalidArgument)
+	}
+	_, err = client.GetPost(context.Background(), connect.NewRequest(&kitchenv1.GetPostRequest{Id: "1"}))
+	if code := connect.CodeOf(err); code != connect.CodeUnimplemented {
+		t.Errorf("GetPost with an id = %v, want %v", code, connect.CodeUnimplemented)
+	}
+}
@@ -0,0 +1,95 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	common_errors "kitchen/pkg/common/errors"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/protovalidate-go"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// Interceptor is a validation interceptor that validates request messages
+// against their protovalidate constraints before they reach the handler.
+// Invalid requests are rejected with an InvalidArgument domain error carrying
+// a violation per field, which the error interceptor converts to
+// CodeInvalidArgument
+type Interceptor struct {
+	validator *protovalidate.Validator
+}
+
+// NewInterceptor creates a new connect validation interceptor
+func NewInterceptor() (*Interceptor, error) {
+	validator, err := protovalidate.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator: %w", err)
+	}
+	return &Interceptor{validator: validator}, nil
+}
+
+// WrapUnary wraps a unary call validating the request message
+func (i *Interceptor) WrapUnary(fn connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.validate(request.Any()); err != nil {
+			return nil, err
+		}
+		return fn(ctx, request)
+	}
+}
+
+// WrapStreamingClient returns the client call unchanged
+func (i *Interceptor) WrapStreamingClient(fn connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return fn
+}
+
+// WrapStreamingHandler wraps a streaming handler call validating each received
+// message
+func (i *Interceptor) WrapStreamingHandler(fn connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return fn(ctx, &streamingHandlerConn{StreamingHandlerConn: conn, interceptor: i})
+	}
+}
+
+// validate validates the message, converting constraint violations to an
+// InvalidArgument domain error
+func (i *Interceptor) validate(msg any) error {
+	message, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+	err := i.validator.Validate(message)
+	if err == nil {
+		return nil
+	}
+	var validationErr *protovalidate.ValidationError
+	if !errors.As(err, &validationErr) {
+		// The constraints themselves are broken, this is a server bug
+		return fmt.Errorf("failed to validate %s: %w", message.ProtoReflect().Descriptor().FullName(), err)
+	}
+	violations := make([]common_errors.FieldViolation, 0, len(validationErr.Violations))
+	for _, violation := range validationErr.Violations {
+		violations = append(violations, common_errors.FieldViolation{
+			Field:       violation.GetFieldPath(),
+			Description: violation.GetMessage(),
+		})
+	}
+	return common_errors.InvalidArgument("invalid request", violations...).WithReason("VALIDATION_FAILED")
+}
+
+// streamingHandlerConn validates every message received on the stream
+type streamingHandlerConn struct {
+	connect.StreamingHandlerConn
+	interceptor *Interceptor
+}
+
+// Receive receives and validates the next message
+func (c *streamingHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return c.interceptor.validate(msg)
+}
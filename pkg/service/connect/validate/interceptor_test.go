@@ -0,0 +1,103 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	common_errors "kitchen/pkg/common/errors"
+	kitchenv1 "kitchen/proto/gen/kitchen/v1"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestInterceptor creates an interceptor, failing the test when it cannot
+func newTestInterceptor(t *testing.T) *Interceptor {
+	t.Helper()
+	interceptor, err := NewInterceptor()
+	if err != nil {
+		t.Fatalf("NewInterceptor failed: %v", err)
+	}
+	return interceptor
+}
+
+// fields returns the fields of the violations of the error, which must be an
+// InvalidArgument domain error
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	domainErr, ok := common_errors.As(err)
+	if !ok || domainErr.Kind != common_errors.KindInvalidArgument || domainErr.Reason != "VALIDATION_FAILED" {
+		t.Fatalf("error = %v, want an invalid argument with reason VALIDATION_FAILED", err)
+	}
+	fields := make([]string, 0, len(domainErr.Violations))
+	for _, violation := range domainErr.Violations {
+		fields = append(fields, violation.Field)
+	}
+	return fields
+}
+
+func TestValidateUnary(t *testing.T) {
+	calls := 0
+	call := newTestInterceptor(t).WrapUnary(func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+		calls++
+		return connect.NewResponse(&kitchenv1.CreatePostResponse{Id: "1"}), nil
+	})
+
+	// A valid request reaches the handler
+	if _, err := call(context.Background(), connect.NewRequest(&kitchenv1.CreatePostRequest{UserId: "alice"})); err != nil {
+		t.Fatalf("valid request failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+
+	// An invalid request is rejected before it, with a violation per field
+	_, err := call(context.Background(), connect.NewRequest(&kitchenv1.CreatePostRequest{
+		UserId:    "alice smith",
+		ImageUrls: []string{"not a url"},
+	}))
+	got := fields(t, err)
+	if len(got) != 2 || got[0] != "user_id" || got[1] != "image_urls[0]" {
+		t.Errorf("violations = %v, want user_id and image_urls[0]", got)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want the invalid request rejected", calls)
+	}
+}
+
+// streamingConn is a streaming handler connection receiving the messages
+type streamingConn struct {
+	connect.StreamingHandlerConn
+	messages []proto.Message
+}
+
+func (c *streamingConn) Receive(msg any) error {
+	proto.Merge(msg.(proto.Message), c.messages[0])
+	c.messages = c.messages[1:]
+	return nil
+}
+
+func TestValidateStreaming(t *testing.T) {
+	conn := &streamingConn{messages: []proto.Message{
+		&kitchenv1.GetPostRequest{Id: "1"},
+		&kitchenv1.GetPostRequest{},
+	}}
+	var errs []error
+	call := newTestInterceptor(t).WrapStreamingHandler(func(_ context.Context, conn connect.StreamingHandlerConn) error {
+		for range 2 {
+			errs = append(errs, conn.Receive(new(kitchenv1.GetPostRequest)))
+		}
+		return nil
+	})
+	if err := call(context.Background(), conn); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	// Every received message is validated
+	if errs[0] != nil {
+		t.Errorf("valid message = %v, want nil", errs[0])
+	}
+	if got := fields(t, errs[1]); len(got) != 1 || got[0] != "id" {
+		t.Errorf("violations = %v, want id", got)
+	}
+}
@@ -0,0 +1,140 @@
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"kitchen/pkg/common/config"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AnyOrigin is the origin pattern allowing every origin
+const AnyOrigin = "*"
+
+func init() {
+	config.RegisterDefault("cors_max_age", 2*time.Hour)
+}
+
+// Config is the CORS config
+type Config struct {
+	// AllowedOrigins are the origins browsers may call from. Patterns such as
+	// https://*.example.com allow every subdomain, and AnyOrigin every origin.
+	// When empty every origin is allowed in local environments without
+	// credentials, and none otherwise
+	AllowedOrigins []string `config:"cors_allowed_origins"`
+	// AllowCredentials lets browsers send cookies and client certificates
+	AllowCredentials bool `config:"cors_allow_credentials"`
+	// MaxAge is how long browsers cache preflight responses for
+	MaxAge time.Duration `config:"cors_max_age"`
+	// ExposedHeaders are response headers exposed to browsers in addition to
+	// DefaultExposedHeaders
+	ExposedHeaders []string `config:"cors_exposed_headers"`
+}
+
+// Validate validates the config for the environment, returning the violations
+// by config key. Allowing every origin is refused in production, and together
+// with credentials anywhere
+func (c Config) Validate(env config.Env) map[string]error {
+	violations := make(map[string]error)
+	if err := c.validateOrigins(env); err != nil {
+		violations["cors_allowed_origins"] = err
+	}
+	if c.MaxAge < 0 {
+		violations["cors_max_age"] = errors.New("must not be negative")
+	}
+	return violations
+}
+
+// validateOrigins validates the allowed origins for the environment
+func (c Config) validateOrigins(env config.Env) error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == AnyOrigin {
+			if env == config.Production {
+				return errors.New("allowing every origin is not allowed in production")
+			}
+			if c.AllowCredentials {
+				return errors.New("allowing every origin is not allowed with credentials")
+			}
+			continue
+		}
+		if _, err := parsePattern(origin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Options returns the cors options of the config for the environment. Like
+// Validate, it never allows every origin together with credentials
+func (c Config) Options(env config.Env) []Option {
+	origins := c.AllowedOrigins
+	if len(origins) == 0 && env == config.Local && !c.AllowCredentials {
+		origins = []string{AnyOrigin}
+	}
+	patterns := make([]originPattern, 0, len(origins))
+	for _, origin := range origins {
+		pattern, err := parsePattern(origin)
+		if err != nil || (pattern.any && c.AllowCredentials) {
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	exposedHeaders := make([]string, 0, len(DefaultExposedHeaders)+len(c.ExposedHeaders))
+	exposedHeaders = append(exposedHeaders, DefaultExposedHeaders...)
+	exposedHeaders = append(exposedHeaders, c.ExposedHeaders...)
+	return []Option{
+		WithAllowedOriginsFunc(func(origin string) bool {
+			for _, pattern := range patterns {
+				if pattern.matches(origin) {
+					return true
+				}
+			}
+			return false
+		}),
+		WithAllowCredentials(c.AllowCredentials),
+		WithMaxAge(int(c.MaxAge / time.Second)),
+		WithExposedHeaders(exposedHeaders...),
+	}
+}
+
+// originPattern is a parsed allowed origin. A host starting with *. matches
+// every subdomain of the rest of the host, but not the host itself
+type originPattern struct {
+	any    bool
+	scheme string
+	host   string
+	port   string
+}
+
+// parsePattern parses an allowed origin pattern, such as https://example.com
+// or https://*.example.com:8443
+func parsePattern(origin string) (originPattern, error) {
+	if origin == AnyOrigin {
+		return originPattern{any: true}, nil
+	}
+	u, err := url.Parse(strings.ToLower(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return originPattern{}, fmt.Errorf("invalid origin %q, must be of the form scheme://host[:port]", origin)
+	}
+	host := u.Hostname()
+	if strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+		return originPattern{}, fmt.Errorf("invalid origin %q, only a leading *. wildcard is supported", origin)
+	}
+	return originPattern{scheme: u.Scheme, host: host, port: u.Port()}, nil
+}
+
+// matches returns if the origin matches the pattern
+func (p originPattern) matches(origin string) bool {
+	if p.any {
+		return true
+	}
+	u, err := url.Parse(strings.ToLower(origin))
+	if err != nil || u.Scheme != p.scheme || u.Port() != p.port {
+		return false
+	}
+	if suffix, ok := strings.CutPrefix(p.host, "*"); ok {
+		return strings.HasSuffix(u.Hostname(), suffix) && len(u.Hostname()) > len(suffix)
+	}
+	return u.Hostname() == p.host
+}
@@ -0,0 +1,73 @@
+package cors
+
+import (
+	"testing"
+	"time"
+
+	"kitchen/pkg/common/config"
+)
+
+func TestOptionsLocalDefault(t *testing.T) {
+	const origin = "http://localhost:3000"
+
+	// Local environments allow every origin without credentials
+	options := parseOptions(Config{}.Options(config.Local)...)
+	if !options.AllowOriginFunc(origin) {
+		t.Error("local default does not allow every origin")
+	}
+
+	// but never every origin with credentials
+	options = parseOptions(Config{AllowCredentials: true}.Options(config.Local)...)
+	if options.AllowOriginFunc(origin) && options.AllowCredentials {
+		t.Error("local default allows every origin with credentials")
+	}
+
+	// and no origin elsewhere
+	options = parseOptions(Config{}.Options(config.Production)...)
+	if options.AllowOriginFunc(origin) {
+		t.Error("production default allows an origin")
+	}
+}
+
+func TestOptionsAnyOriginWithCredentials(t *testing.T) {
+
+	// An unvalidated config allowing every origin with credentials still
+	// allows only the origins it names
+	options := parseOptions(Config{
+		AllowedOrigins:   []string{AnyOrigin, "https://app.example.com"},
+		AllowCredentials: true,
+	}.Options(config.Local)...)
+	if options.AllowOriginFunc("https://evil.example.org") {
+		t.Error("every origin is allowed with credentials")
+	}
+	if !options.AllowOriginFunc("https://app.example.com") {
+		t.Error("a named origin is not allowed with credentials")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		cfg  Config
+		env  config.Env
+		want []string
+	}{
+		{"valid", Config{AllowedOrigins: []string{"https://*.example.com"}}, config.Production, nil},
+		{"any origin in production", Config{AllowedOrigins: []string{AnyOrigin}}, config.Production, []string{"cors_allowed_origins"}},
+		{"any origin with credentials", Config{AllowedOrigins: []string{AnyOrigin}, AllowCredentials: true}, config.Local, []string{"cors_allowed_origins"}},
+		{"invalid origin", Config{AllowedOrigins: []string{"example.com"}}, config.Local, []string{"cors_allowed_origins"}},
+		{"negative max age", Config{MaxAge: -time.Second}, config.Local, []string{"cors_max_age"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			violations := test.cfg.Validate(test.env)
+			if len(violations) != len(test.want) {
+				t.Fatalf("violations = %v, want %v", violations, test.want)
+			}
+			for _, key := range test.want {
+				if violations[key] == nil {
+					t.Errorf("violations = %v, want one for %s", violations, key)
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,26 @@ import (
 	"github.com/rs/cors"
 )
 
+// DefaultExposedHeaders are the response headers exposed to browsers by
+// default
+var DefaultExposedHeaders = []string{
+	// Content-Type is in the default safelist.
+	"Accept",
+	"Accept-Encoding",
+	"Accept-Post",
+	"Connect-Accept-Encoding",
+	"Connect-Content-Encoding",
+	"Content-Encoding",
+	"Grpc-Accept-Encoding",
+	"Grpc-Encoding",
+	"Grpc-Message",
+	"Grpc-Status",
+	"Grpc-Status-Details-Bin",
+	"Idempotency-Replayed",
+	"Retry-After",
+	"X-Request-Id",
+}
+
 // New create a new CORS configuration
 func New(opts ...Option) *cors.Cors {
 	return cors.New(parseOptions(opts...))
@@ -24,24 +44,11 @@ func parseOptions(opts ...Option) cors.Options {
 			http.MethodDelete,
 		},
 		AllowOriginFunc: func(origin string) bool {
-			// Allow all origins, which effectively disables CORS.
-			return true
+			// Allow no cross-origin requests unless origins are configured
+			return false
 		},
 		AllowedHeaders: []string{"*"},
-		ExposedHeaders: []string{
-			// Content-Type is in the default safelist.
-			"Accept",
-			"Accept-Encoding",
-			"Accept-Post",
-			"Connect-Accept-Encoding",
-			"Connect-Content-Encoding",
-			"Content-Encoding",
-			"Grpc-Accept-Encoding",
-			"Grpc-Encoding",
-			"Grpc-Message",
-			"Grpc-Status",
-			"Grpc-Status-Details-Bin",
-		},
+		ExposedHeaders: DefaultExposedHeaders,
 		// Let browsers cache CORS information for longer, which reduces the
 		// number of preflight requests. Any changes to ExposedHeaders won't
 		// take effect until the cached data expires. FF caps this value at 24h,
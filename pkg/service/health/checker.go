@@ -0,0 +1,249 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"kitchen/pkg/common/logging"
+	"sort"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpchealth"
+	"go.uber.org/zap"
+)
+
+// LoggerName the logger name to use for the checker
+const LoggerName = "health.checker"
+
+// WatchInterval is how often the statuses of watched services are re-checked,
+// watchers are notified when one changes. Shutdown and registrations are
+// pushed to watchers immediately
+const WatchInterval = 5 * time.Second
+
+var _ grpchealth.Checker = (*Checker)(nil)
+
+// Check checks a dependency, returning an error when it is unhealthy
+type Check func(context.Context) error
+
+// Checker reports the health of the process and its services. A service is
+// serving when every check registered for it and for the process passes, and
+// the checker has not been shut down
+type Checker struct {
+	logger   *zap.Logger
+	interval time.Duration
+
+	mu       sync.RWMutex
+	services map[string]struct{}
+	checks   map[string]map[string]Check
+	shutdown bool
+	// changed is closed and replaced whenever a status may have changed, and
+	// left closed once shut down
+	changed chan struct{}
+	// watchers counts the watchers of each service, and stopMonitor stops
+	// re-checking their statuses once there are none
+	watchers    map[string]int
+	stopMonitor chan struct{}
+}
+
+// NewChecker creates a new checker for the supplied fully-qualified service
+// names
+func NewChecker(services ...string) *Checker {
+	c := &Checker{
+		logger:   logging.NewLogger(LoggerName),
+		interval: WatchInterval,
+		services: make(map[string]struct{}, len(services)),
+		checks:   make(map[string]map[string]Check),
+		changed:  make(chan struct{}),
+		watchers: make(map[string]int),
+	}
+	c.RegisterService(services...)
+	return c
+}
+
+// RegisterService registers services the checker reports on
+func (c *Checker) RegisterService(services ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, service := range services {
+		c.services[service] = struct{}{}
+	}
+	c.notify()
+}
+
+// RegisterCheck registers the named check for the service, replacing any check
+// with the same name. Checks registered for the empty service apply to the
+// process and so to every service
+func (c *Checker) RegisterCheck(service, name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if service != "" {
+		c.services[service] = struct{}{}
+	}
+	if c.checks[service] == nil {
+		c.checks[service] = make(map[string]Check)
+	}
+	c.checks[service][name] = check
+	c.notify()
+}
+
+// Shutdown marks every service as not serving and notifies the watchers. It is
+// called as the server begins to stop
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shutdown {
+		return
+	}
+	c.shutdown = true
+	close(c.changed)
+}
+
+// Check implements grpchealth.Checker, unknown services are reported as
+// connect.CodeNotFound
+func (c *Checker) Check(ctx context.Context, req *grpchealth.CheckRequest) (*grpchealth.CheckResponse, error) {
+	status, ok := c.status(ctx, req.Service)
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown service %s", req.Service))
+	}
+	return &grpchealth.CheckResponse{Status: status}, nil
+}
+
+// Watch calls send with the status of the service whenever the checker
+// notifies it changed, until the context is done or send fails. Known is
+// false while the service is not registered. Once the checker is shut down the
+// final status is sent and Watch returns, so open streams do not hold up the
+// server stopping
+func (c *Checker) Watch(ctx context.Context, service string, send func(status grpchealth.Status, known bool) error) error {
+	c.watch(service)
+	defer c.unwatch(service)
+	var sent bool
+	var lastStatus grpchealth.Status
+	var lastKnown bool
+	for {
+		c.mu.RLock()
+		changed, shutdown := c.changed, c.shutdown
+		c.mu.RUnlock()
+
+		status, known := c.status(ctx, service)
+		if !sent || status != lastStatus || known != lastKnown {
+			if err := send(status, known); err != nil {
+				return err
+			}
+			sent, lastStatus, lastKnown = true, status, known
+		}
+		if shutdown {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// notify wakes the watchers to send any changed status, c.mu must be held
+func (c *Checker) notify() {
+	if c.shutdown {
+		return
+	}
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// watch adds a watcher of the service, starting to monitor the statuses of
+// the watched services for the first
+func (c *Checker) watch(service string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watchers[service]++
+	if c.stopMonitor == nil {
+		c.stopMonitor = make(chan struct{})
+		go c.monitor(c.stopMonitor)
+	}
+}
+
+// unwatch removes a watcher of the service, stopping monitoring after the last
+func (c *Checker) unwatch(service string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.watchers[service]--; c.watchers[service] == 0 {
+		delete(c.watchers, service)
+	}
+	if len(c.watchers) == 0 {
+		close(c.stopMonitor)
+		c.stopMonitor = nil
+	}
+}
+
+// monitor checks the statuses of the watched services every interval until
+// stopped, notifying the watchers when any changed
+func (c *Checker) monitor(stop <-chan struct{}) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	last := make(map[string]grpchealth.Status)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.RLock()
+		services := make([]string, 0, len(c.watchers))
+		for service := range c.watchers {
+			services = append(services, service)
+		}
+		c.mu.RUnlock()
+
+		changed := false
+		statuses := make(map[string]grpchealth.Status, len(services))
+		for _, service := range services {
+			ctx, cancel := context.WithTimeout(context.Background(), c.interval)
+			statuses[service], _ = c.status(ctx, service)
+			cancel()
+			if previous, ok := last[service]; !ok || previous != statuses[service] {
+				changed = true
+			}
+		}
+		last = statuses
+		if changed {
+			c.mu.Lock()
+			c.notify()
+			c.mu.Unlock()
+		}
+	}
+}
+
+// status runs the checks for the service, ok is false when the service is not
+// registered
+func (c *Checker) status(ctx context.Context, service string) (status grpchealth.Status, ok bool) {
+	c.mu.RLock()
+	if _, registered := c.services[service]; service != "" && !registered {
+		c.mu.RUnlock()
+		return grpchealth.StatusUnknown, false
+	}
+	if c.shutdown {
+		c.mu.RUnlock()
+		return grpchealth.StatusNotServing, true
+	}
+	names := make([]string, 0, len(c.checks[""])+len(c.checks[service]))
+	checks := make(map[string]Check, cap(names))
+	for _, scope := range []string{"", service} {
+		for name, check := range c.checks[scope] {
+			names = append(names, name)
+			checks[name] = check
+		}
+	}
+	c.mu.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		if err := checks[name](ctx); err != nil {
+			c.logger.Warn("health check failed", zap.String("service", service), zap.String("check", name), zap.Error(err))
+			return grpchealth.StatusNotServing, true
+		}
+	}
+	return grpchealth.StatusServing, true
+}
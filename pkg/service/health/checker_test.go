@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/grpchealth"
+)
+
+const service = "kitchen.v1.KitchenService"
+
+// update is a status sent to a watcher
+type update struct {
+	status grpchealth.Status
+	known  bool
+}
+
+// watch watches the service on the checker, returning the sent statuses and
+// the result of Watch
+func watch(t *testing.T, c *Checker, service string) (<-chan update, <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	updates, result := make(chan update, 10), make(chan error, 1)
+	go func() {
+		result <- c.Watch(ctx, service, func(status grpchealth.Status, known bool) error {
+			updates <- update{status, known}
+			return nil
+		})
+	}()
+	return updates, result
+}
+
+// next returns the next sent status, failing when none is sent within a second
+func next(t *testing.T, updates <-chan update) update {
+	t.Helper()
+	select {
+	case u := <-updates:
+		return u
+	case <-time.After(time.Second):
+		t.Fatal("no status was sent")
+		return update{}
+	}
+}
+
+func TestWatchNotifiesChanges(t *testing.T) {
+	c := NewChecker(service)
+	c.interval = 10 * time.Millisecond
+	var failing atomic.Bool
+	c.RegisterCheck("", "store", func(context.Context) error {
+		if failing.Load() {
+			return errors.New("store unavailable")
+		}
+		return nil
+	})
+	updates, result := watch(t, c, service)
+
+	// The current status is sent, then every change the checker notices
+	if u := next(t, updates); u != (update{grpchealth.StatusServing, true}) {
+		t.Fatalf("initial status = %+v, want serving", u)
+	}
+	failing.Store(true)
+	if u := next(t, updates); u != (update{grpchealth.StatusNotServing, true}) {
+		t.Fatalf("status after the check failed = %+v, want not serving", u)
+	}
+	failing.Store(false)
+	if u := next(t, updates); u != (update{grpchealth.StatusServing, true}) {
+		t.Fatalf("status after the check recovered = %+v, want serving", u)
+	}
+
+	// An unchanged status is not sent again
+	select {
+	case u := <-updates:
+		t.Fatalf("unchanged status sent again: %+v", u)
+	case <-time.After(10 * c.interval):
+	}
+
+	// Shutdown sends the final status and ends the watch
+	c.Shutdown()
+	if u := next(t, updates); u != (update{grpchealth.StatusNotServing, true}) {
+		t.Fatalf("status after shutdown = %+v, want not serving", u)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+}
+
+func TestWatchNotifiesRegistration(t *testing.T) {
+	c := NewChecker()
+
+	// Registrations are pushed at once, without waiting for a re-check
+	updates, _ := watch(t, c, service)
+	if u := next(t, updates); u.known {
+		t.Fatalf("initial status = %+v, want unknown", u)
+	}
+	c.RegisterService(service)
+	if u := next(t, updates); u != (update{grpchealth.StatusServing, true}) {
+		t.Fatalf("status after registering = %+v, want serving", u)
+	}
+	c.RegisterCheck(service, "broken", func(context.Context) error { return errors.New("broken") })
+	if u := next(t, updates); u != (update{grpchealth.StatusNotServing, true}) {
+		t.Fatalf("status after registering a failing check = %+v, want not serving", u)
+	}
+}
+
+func TestWatchStopsMonitoring(t *testing.T) {
+	c := NewChecker(service)
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- c.Watch(ctx, service, func(grpchealth.Status, bool) error { return nil })
+	}()
+	cancel()
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Watch = %v, want context.Canceled", err)
+	}
+
+	// The statuses are no longer re-checked once nothing watches them
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.stopMonitor != nil || len(c.watchers) != 0 {
+		t.Errorf("still monitoring %v after the last watcher left", c.watchers)
+	}
+}
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpchealth"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// NewHandler creates a handler for the grpc.health.v1.Health service backed by
+// the checker. Check is served by grpchealth, which does not support Watch, so
+// Watch is served here by streaming the checker's status changes
+func NewHandler(checker *Checker, opts ...connect.HandlerOption) (string, http.Handler) {
+	path, check := grpchealth.NewHandler(checker, opts...)
+	watch := connect.NewServerStreamHandler(
+		path+"Watch",
+		func(ctx context.Context, req *connect.Request[healthv1.HealthCheckRequest], stream *connect.ServerStream[healthv1.HealthCheckResponse]) error {
+			return checker.Watch(ctx, req.Msg.GetService(), func(status grpchealth.Status, known bool) error {
+				resp := &healthv1.HealthCheckResponse{Status: healthv1.HealthCheckResponse_ServingStatus(status)}
+				if !known {
+					resp.Status = healthv1.HealthCheckResponse_SERVICE_UNKNOWN
+				}
+				return stream.Send(resp)
+			})
+		},
+		opts...,
+	)
+	return path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/Watch") {
+			watch.ServeHTTP(w, r)
+			return
+		}
+		check.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,100 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired records are dropped
+const sweepInterval = time.Minute
+
+// Ensure MemoryStore conforms to Store
+var _ Store = (*MemoryStore)(nil)
+
+// entry is a stored record and when it expires
+type entry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store holding the records in memory. Records are lost on
+// restart and not shared between replicas
+type MemoryStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]entry
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// Begin claims the key for a request
+func (s *MemoryStore) Begin(_ context.Context, key string, requestHash []byte, ttl time.Duration) (*Record, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		record := e.record
+		return &record, nil
+	}
+	s.entries[key] = entry{
+		record:    Record{RequestHash: requestHash},
+		expiresAt: now.Add(ttl),
+	}
+	return nil, nil
+}
+
+// Extend extends the claim on the key
+func (s *MemoryStore) Extend(_ context.Context, key string, ttl time.Duration) error {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && !e.record.Completed && now.Before(e.expiresAt) {
+		e.expiresAt = now.Add(ttl)
+		s.entries[key] = e
+	}
+	return nil
+}
+
+// Complete stores the response of the request that claimed the key
+func (s *MemoryStore) Complete(_ context.Context, key string, record Record, ttl time.Duration) error {
+	record.Completed = true
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry{record: record, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+// Release releases the claim on the key
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep drops the expired records
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record is what is stored for an idempotency key
+type Record struct {
+	// RequestHash is the hash of the request message the key was first used
+	// with
+	RequestHash []byte
+	// Completed is false while the first request with the key is in flight
+	Completed bool
+	// ResponseType is the full name of the response message type, and
+	// Response the message in the binary wire format
+	ResponseType string
+	Response     []byte
+}
+
+// Store stores idempotency records. Records expire after their TTL, after
+// which the key may be used again
+type Store interface {
+	// Begin claims the key for a request with the hash, storing an incomplete
+	// record for the TTL. When the key is already claimed it returns the
+	// existing record instead, and nil once claimed
+	Begin(ctx context.Context, key string, requestHash []byte, ttl time.Duration) (*Record, error)
+	// Extend extends the claim on the key by the TTL while the request is in
+	// flight. It does nothing once the key is completed or released
+	Extend(ctx context.Context, key string, ttl time.Duration) error
+	// Complete stores the response of the request that claimed the key for the
+	// TTL
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release releases the claim on the key, so the request can be retried
+	Release(ctx context.Context, key string) error
+}
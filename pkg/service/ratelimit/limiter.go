@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+const (
+	// KeyByCaller keys buckets by the authenticated user, falling back to the
+	// client IP for anonymous callers
+	KeyByCaller = "caller"
+	// KeyByUser keys buckets by the authenticated user, anonymous callers
+	// are not limited
+	KeyByUser = "user"
+	// KeyByAPIKey keys buckets by the API key, falling back to the client IP
+	// for callers without one. It is never used unless configured, as a
+	// caller rotating keys gets a new bucket with every key
+	KeyByAPIKey = "api_key"
+	// KeyByIP keys buckets by the client IP
+	KeyByIP = "ip"
+	// AnyProcedure is the procedure of the rule applied to procedures without
+	// a rule of their own
+	AnyProcedure = "*"
+)
+
+// Limit is a token bucket allowing Requests per Period on average, and bursts
+// of up to Burst requests
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Rule is the rate limit of a single procedure
+type Rule struct {
+	// Procedure is the procedure the rule applies to, such as
+	// /kitchen.v1.KitchenService/CreatePost, or AnyProcedure
+	Procedure string `config:"procedure"`
+	// Requests is the number of requests allowed per Period
+	Requests int           `config:"requests"`
+	Period   time.Duration `config:"period"`
+	// Burst is the number of requests allowed at once, defaults to Requests
+	Burst int `config:"burst"`
+	// KeyBy is what buckets are keyed by, defaults to KeyByCaller
+	KeyBy string `config:"key_by"`
+}
+
+// Limit returns the token bucket of the rule
+func (r Rule) Limit() Limit {
+	burst := r.Burst
+	if burst <= 0 {
+		burst = r.Requests
+	}
+	return Limit{Requests: r.Requests, Period: r.Period, Burst: burst}
+}
+
+// Validate validates the rule
+func (r Rule) Validate() error {
+	switch {
+	case r.Procedure == "":
+		return errors.New("procedure is required")
+	case r.Requests <= 0:
+		return fmt.Errorf("requests of procedure %q must be positive", r.Procedure)
+	case r.Period <= 0:
+		return fmt.Errorf("period of procedure %q must be positive", r.Procedure)
+	case r.Burst < 0:
+		return fmt.Errorf("burst of procedure %q must not be negative", r.Procedure)
+	}
+	switch r.KeyBy {
+	case "", KeyByCaller, KeyByUser, KeyByAPIKey, KeyByIP:
+	default:
+		return fmt.Errorf("unknown key_by %q of procedure %q", r.KeyBy, r.Procedure)
+	}
+	return nil
+}
+
+// ValidateRules validates the rules, and that no procedure has more than one
+func ValidateRules(rules []Rule) error {
+	seen := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[rule.Procedure]; ok {
+			return fmt.Errorf("duplicate rule for procedure %q", rule.Procedure)
+		}
+		seen[rule.Procedure] = struct{}{}
+	}
+	return nil
+}
+
+// ParseTrustedProxies parses the addresses of trusted proxies, which are IPs
+// or CIDR prefixes such as 10.0.0.0/8
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// Limiter takes tokens from token buckets. The in-memory limiter limits each
+// replica on its own, a limiter backed by a shared store limits them together
+type Limiter interface {
+	// Take takes a token from the bucket of the key, creating it full if it
+	// does not exist. When the bucket is empty it returns false and how long
+	// until a token is available
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
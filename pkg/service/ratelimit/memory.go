@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// sweepInterval is how often buckets that have refilled are dropped
+	sweepInterval = time.Minute
+	// DefaultMaxBuckets is the number of buckets a memory limiter holds when
+	// no maximum is set
+	DefaultMaxBuckets = 100_000
+)
+
+// Ensure MemoryLimiter conforms to Limiter
+var _ Limiter = (*MemoryLimiter)(nil)
+
+// bucket is a token bucket, its tokens are refilled lazily when taken from
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// MemoryLimiter is a Limiter holding the buckets in memory. The number of
+// buckets is bounded, when full the least recently used bucket is evicted, so
+// callers rotating through keys cannot exhaust memory
+type MemoryLimiter struct {
+	now        func() time.Time
+	maxBuckets int
+
+	mu        sync.Mutex
+	buckets   map[string]*list.Element
+	lru       *list.List
+	lastSweep time.Time
+}
+
+// NewMemoryLimiter creates a new in-memory limiter holding at most maxBuckets
+// buckets, defaulting to DefaultMaxBuckets when it is not positive
+func NewMemoryLimiter(maxBuckets int) *MemoryLimiter {
+	if maxBuckets <= 0 {
+		maxBuckets = DefaultMaxBuckets
+	}
+	return &MemoryLimiter{
+		now:        time.Now,
+		maxBuckets: maxBuckets,
+		buckets:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Take takes a token from the bucket of the key
+func (l *MemoryLimiter) Take(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	now := l.now()
+	rate := float64(limit.Requests) / limit.Period.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b := l.bucket(key, limit, now)
+	b.tokens = min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// bucket returns the bucket of the key marked most recently used, creating it
+// full and evicting the least recently used bucket when there is no room
+func (l *MemoryLimiter) bucket(key string, limit Limit, now time.Time) *bucket {
+	if element, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(element)
+		b := element.Value.(*bucket)
+		if b.limit != limit {
+			b.tokens, b.last, b.limit = float64(limit.Burst), now, limit
+		}
+		return b
+	}
+	for l.lru.Len() >= l.maxBuckets {
+		l.remove(l.lru.Back())
+	}
+	b := &bucket{key: key, tokens: float64(limit.Burst), last: now, limit: limit}
+	l.buckets[key] = l.lru.PushFront(b)
+	return b
+}
+
+// remove removes the bucket in the element
+func (l *MemoryLimiter) remove(element *list.Element) {
+	l.lru.Remove(element)
+	delete(l.buckets, element.Value.(*bucket).key)
+}
+
+// sweep drops the buckets that have refilled, they are recreated full when
+// next taken from
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for element := l.lru.Front(); element != nil; {
+		next := element.Next()
+		b := element.Value.(*bucket)
+		rate := float64(b.limit.Requests) / b.limit.Period.Seconds()
+		if b.tokens+now.Sub(b.last).Seconds()*rate >= float64(b.limit.Burst) {
+			l.remove(element)
+		}
+		element = next
+	}
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiterTake(t *testing.T) {
+	now := time.Now()
+	l := NewMemoryLimiter(0)
+	l.now = func() time.Time { return now }
+	limit := Limit{Requests: 1, Period: time.Second, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		if allowed, _, _ := l.Take(context.Background(), "a", limit); !allowed {
+			t.Fatalf("take %d within the burst was refused", i)
+		}
+	}
+	allowed, wait, _ := l.Take(context.Background(), "a", limit)
+	if allowed || wait != time.Second {
+		t.Fatalf("take beyond the burst = %t, %v, want false, 1s", allowed, wait)
+	}
+	now = now.Add(time.Second)
+	if allowed, _, _ := l.Take(context.Background(), "a", limit); !allowed {
+		t.Fatal("take after refilling was refused")
+	}
+}
+
+func TestMemoryLimiterEvictsLeastRecentlyUsed(t *testing.T) {
+	l := NewMemoryLimiter(2)
+	limit := Limit{Requests: 1, Period: time.Hour, Burst: 1}
+	take := func(key string) bool {
+		allowed, _, _ := l.Take(context.Background(), key, limit)
+		return allowed
+	}
+
+	take("a")
+	take("b")
+	take("a")
+	for i := 0; i < 10; i++ {
+		take(fmt.Sprintf("rotating-%d", i))
+		take("a")
+	}
+	if len(l.buckets) != 2 || l.lru.Len() != 2 {
+		t.Fatalf("buckets = %d, want 2", len(l.buckets))
+	}
+
+	// The bucket kept in use survives, and stays empty
+	if take("a") {
+		t.Error("the recently used bucket was evicted")
+	}
+	if !take("b") {
+		t.Error("the least recently used bucket was not evicted")
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"kitchen/pkg/common/logging"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunnerLoggerName the logger name to use for the runner
+const RunnerLoggerName = "service.runner"
+
+// ErrNotReady is returned by Runner.CheckReady while the servers are starting
+// or shutting down
+var ErrNotReady = errors.New("not ready")
+
+// Server is a server the Runner starts and stops
+type Server interface {
+	// Start starts the server, blocking until it is stopped
+	Start(context.Context) error
+	// Stop stops the server, forcing it closed when the context deadline
+	// passes
+	Stop(context.Context) error
+}
+
+// listener is implemented by servers that report when they are accepting
+// connections. Servers without it are considered ready once started
+type listener interface {
+	Listening() <-chan struct{}
+}
+
+// Runner runs a set of servers until the process is signalled to stop, then
+// shuts them down gracefully
+type Runner struct {
+	cfg     Config
+	servers []Server
+	logger  *zap.Logger
+
+	// mu guards the transitions of ready, once stopping it is never set again
+	mu       sync.Mutex
+	stopping bool
+	ready    atomic.Bool
+}
+
+// NewRunner creates a new runner for the supplied servers
+func NewRunner(cfg Config, servers ...Server) *Runner {
+	return &Runner{
+		cfg:     cfg,
+		servers: servers,
+		logger:  logging.NewLogger(RunnerLoggerName),
+	}
+}
+
+// Ready returns if the servers are ready to receive traffic. It is false until
+// every server is listening and again once shutdown begins
+func (r *Runner) Ready() bool {
+	return r.ready.Load()
+}
+
+// CheckReady returns ErrNotReady unless the runner is ready, so it can back a
+// readiness probe
+func (r *Runner) CheckReady(context.Context) error {
+	if !r.Ready() {
+		return ErrNotReady
+	}
+	return nil
+}
+
+// Run starts the servers and blocks until SIGINT or SIGTERM is received, the
+// context is cancelled or a server fails. The servers are then marked
+// not-ready, left to drain for the configured delay and stopped with the
+// configured deadline
+func (r *Runner) Run(ctx context.Context) error {
+
+	// Trap the stop signals. Once we begin shutting down the handlers are
+	// released, so a second signal terminates the process immediately
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start the servers, marking them ready once they are all listening
+	errs := make(chan error, len(r.servers))
+	for _, server := range r.servers {
+		go func(server Server) {
+			errs <- server.Start(ctx)
+		}(server)
+	}
+	go r.markReady(ctx)
+
+	// Wait for a reason to stop
+	var runErr error
+	select {
+	case err := <-errs:
+		if !errors.Is(err, http.ErrServerClosed) {
+			r.logger.Error("server failed", zap.Error(err))
+			runErr = err
+		}
+	case <-ctx.Done():
+		r.logger.Info("received shutdown signal")
+	}
+	stop()
+	r.mu.Lock()
+	r.stopping = true
+	r.ready.Store(false)
+	r.mu.Unlock()
+
+	// Keep serving while load balancers notice we are no longer ready. There
+	// is nothing to drain when a server failed
+	if runErr == nil && r.cfg.ShutdownDrainDelay > 0 {
+		r.logger.Info("draining connections", zap.Duration("delay", r.cfg.ShutdownDrainDelay))
+		time.Sleep(r.cfg.ShutdownDrainDelay)
+	}
+
+	// Stop the servers, sharing the one deadline
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), r.cfg.ShutdownTimeout)
+	defer cancel()
+	stopErrs := make([]error, 0, len(r.servers)+1)
+	stopErrs = append(stopErrs, runErr)
+	for _, server := range r.servers {
+		stopErrs = append(stopErrs, server.Stop(shutdownCtx))
+	}
+	return errors.Join(stopErrs...)
+}
+
+// markReady marks the runner ready once every server is listening
+func (r *Runner) markReady(ctx context.Context) {
+	for _, server := range r.servers {
+		l, ok := server.(listener)
+		if !ok {
+			continue
+		}
+		select {
+		case <-l.Listening():
+		case <-ctx.Done():
+			return
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.stopping {
+		r.ready.Store(true)
+		r.logger.Info("ready to receive traffic")
+	}
+}
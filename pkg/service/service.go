@@ -7,8 +7,9 @@ import "context"
 type PreStartHook func(context.Context) error
 
 // PreShutdownHook is a function that will be called right before a service is
-// stopped
-type PreShutdownHook func() error
+// stopped. The context carries the shutdown deadline
+type PreShutdownHook func(context.Context) error
 
-// ShutdownHook is a function that will be called when the service is stopping
-type ShutdownHook func() error
+// ShutdownHook is a function that will be called when the service is stopping.
+// The context carries the shutdown deadline
+type ShutdownHook func(context.Context) error
@@ -0,0 +1,80 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// checkTimeout is the deadline for all the checks of a single probe
+const checkTimeout = 5 * time.Second
+
+// HealthCheck checks a dependency of the service, returning an error when it
+// is unhealthy
+type HealthCheck func(context.Context) error
+
+// healthChecks is a named set of health checks
+type healthChecks struct {
+	mu     sync.RWMutex
+	checks map[string]HealthCheck
+}
+
+// register registers the named check, replacing any check with the same name
+func (h *healthChecks) register(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.checks == nil {
+		h.checks = make(map[string]HealthCheck)
+	}
+	h.checks[name] = check
+}
+
+// healthResponse is the body of a probe response
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// ServeHTTP runs every check concurrently, responding 200 when all of them
+// pass and 503 otherwise
+func (h *healthChecks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}(i, h.checks[name])
+	}
+	h.mu.RUnlock()
+	wg.Wait()
+
+	resp := healthResponse{Status: "ok", Checks: make(map[string]string, len(names))}
+	code := http.StatusOK
+	for i, name := range names {
+		if errs[i] != nil {
+			resp.Checks[name] = errs[i].Error()
+			resp.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[name] = "ok"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,130 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kitchen/pkg/common/config"
+	"kitchen/pkg/common/logging"
+	"kitchen/pkg/service"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"go.uber.org/zap"
+)
+
+// LoggerName the logger name to use for the server
+const LoggerName = "system.server"
+
+var _ service.Server = (*Server)(nil)
+
+// Server is the system server. It serves the health probes, profiling and
+// config endpoints on the system port, separately from the service traffic
+//
+//	/healthz       liveness, backed by the registered liveness checks
+//	/readyz        readiness, backed by the registered readiness checks
+//	/config        the loaded config with secure values masked
+//	/debug/pprof/  the runtime profiles, when the profiler is enabled
+type Server struct {
+	logger     *zap.Logger
+	httpServer *http.Server
+	mux        *http.ServeMux
+	liveness   healthChecks
+	readiness  healthChecks
+	cfg        service.Config
+	listening  chan struct{}
+}
+
+// NewServer creates a new system server
+func NewServer(cfg service.Config) *Server {
+	s := &Server{
+		cfg:       cfg,
+		logger:    logging.NewLogger(LoggerName),
+		mux:       http.NewServeMux(),
+		listening: make(chan struct{}),
+	}
+	s.mux.Handle("GET /healthz", &s.liveness)
+	s.mux.Handle("GET /readyz", &s.readiness)
+	s.mux.HandleFunc("GET /config", s.config)
+
+	// Only expose the profiles when asked to, they are expensive and leak
+	// details of the process
+	if cfg.ProfilerEnabled {
+		runtime.SetBlockProfileRate(cfg.ProfilerBlockProfileRate)
+		runtime.SetMutexProfileFraction(cfg.ProfilerMutexProfileFraction)
+		s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+		s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	s.httpServer = &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.BindAddress, cfg.SystemPort),
+		Handler:           s.mux,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+	}
+	return s
+}
+
+// RegisterLivenessCheck registers a check reported by /healthz. A failing
+// liveness check means the process should be restarted, so only register
+// checks a restart can fix
+func (s *Server) RegisterLivenessCheck(name string, check HealthCheck) {
+	s.liveness.register(name, check)
+}
+
+// RegisterReadinessCheck registers a check reported by /readyz. A failing
+// readiness check takes the instance out of rotation until it passes
+func (s *Server) RegisterReadinessCheck(name string, check HealthCheck) {
+	s.readiness.register(name, check)
+}
+
+// Handle registers an additional handler on the system server
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start starts this server, this function will block until Stop is called
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("system service listening on port", zap.String("addr", s.cfg.BindAddress), zap.Int("port", s.cfg.SystemPort))
+	close(s.listening)
+	return s.httpServer.Serve(lis)
+}
+
+// Listening returns a channel that is closed once the server is accepting
+// connections
+func (s *Server) Listening() <-chan struct{} {
+	return s.listening
+}
+
+// Stop stops this server, closing any remaining connections when the context
+// deadline passes
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("stopping system service")
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		err = errors.Join(err, s.httpServer.Close())
+	}
+	return err
+}
+
+// config writes the loaded config as JSON, masking the secure values
+func (s *Server) config(w http.ResponseWriter, r *http.Request) {
+	cfg := config.LoadedConfig()
+	if cfg == nil {
+		http.Error(w, "config not loaded", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := cfg.WriteTo(w); err != nil {
+		s.logger.Warn("failed to write config", zap.Error(err))
+	}
+}
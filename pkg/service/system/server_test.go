@@ -0,0 +1,146 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kitchen/pkg/common/config"
+	"kitchen/pkg/common/metrics"
+	"kitchen/pkg/service"
+
+	"go.opentelemetry.io/otel"
+)
+
+// newTestServer serves the system server for the config over HTTP
+func newTestServer(t *testing.T, cfg service.Config) (*Server, *httptest.Server) {
+	t.Helper()
+	s := NewServer(cfg)
+	server := httptest.NewServer(s.httpServer.Handler)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+// get gets the path from the server, returning the status code and body
+func get(t *testing.T, server *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := server.Client().Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestHealthProbes(t *testing.T) {
+	s, server := newTestServer(t, service.Config{})
+	s.RegisterLivenessCheck("process", func(context.Context) error { return nil })
+	s.RegisterReadinessCheck("process", func(context.Context) error { return nil })
+	s.RegisterReadinessCheck("store", func(context.Context) error { return errors.New("store unavailable") })
+
+	for _, test := range []struct {
+		path   string
+		code   int
+		status string
+		checks map[string]string
+	}{
+		{"/healthz", http.StatusOK, "ok", map[string]string{"process": "ok"}},
+		{"/readyz", http.StatusServiceUnavailable, "unavailable", map[string]string{"process": "ok", "store": "store unavailable"}},
+	} {
+		code, body := get(t, server, test.path)
+		if code != test.code {
+			t.Errorf("%s status = %d, want %d", test.path, code, test.code)
+		}
+		var resp healthResponse
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			t.Fatalf("failed to decode %s: %v", test.path, err)
+		}
+		if resp.Status != test.status || len(resp.Checks) != len(test.checks) {
+			t.Errorf("%s = %+v, want status %s and checks %v", test.path, resp, test.status, test.checks)
+		}
+		for name, want := range test.checks {
+			if resp.Checks[name] != want {
+				t.Errorf("%s check %s = %q, want %q", test.path, name, resp.Checks[name], want)
+			}
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+
+	// The profiles are only served when the profiler is enabled
+	_, server := newTestServer(t, service.Config{})
+	if code, _ := get(t, server, "/debug/pprof/"); code != http.StatusNotFound {
+		t.Errorf("profiles with the profiler disabled = %d, want %d", code, http.StatusNotFound)
+	}
+	_, server = newTestServer(t, service.Config{Base: config.Base{ProfilerEnabled: true}})
+	code, body := get(t, server, "/debug/pprof/")
+	if code != http.StatusOK || !strings.Contains(body, "goroutine") {
+		t.Errorf("profiles with the profiler enabled = %d, want %d listing the profiles", code, http.StatusOK)
+	}
+	if code, _ := get(t, server, "/debug/pprof/goroutine?debug=1"); code != http.StatusOK {
+		t.Errorf("goroutine profile = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestConfigMasksSecureFields(t *testing.T) {
+	t.Setenv("SYSTEM_TEST_NAME", "kitchen")
+	t.Setenv("SYSTEM_TEST_PASSWORD", "hunter2")
+	var cfg struct {
+		Name     string `config:"system_test_name"`
+		Password string `config:"system_test_password,secure"`
+	}
+	if err := config.LoadInto(nil, &cfg); err != nil {
+		t.Fatalf("LoadInto failed: %v", err)
+	}
+	if cfg.Password != "hunter2" {
+		t.Fatalf("loaded password = %q, want hunter2", cfg.Password)
+	}
+
+	// The loaded config is served with the secure values masked
+	_, server := newTestServer(t, service.Config{})
+	code, body := get(t, server, "/config")
+	if code != http.StatusOK {
+		t.Fatalf("config status = %d, want %d", code, http.StatusOK)
+	}
+	var settings map[string]any
+	if err := json.Unmarshal([]byte(body), &settings); err != nil {
+		t.Fatalf("failed to decode config: %v", err)
+	}
+	if settings["system_test_name"] != "kitchen" {
+		t.Errorf("system_test_name = %v, want kitchen", settings["system_test_name"])
+	}
+	if settings["system_test_password"] != strings.Repeat("*", 10) {
+		t.Errorf("system_test_password = %v, want it masked", settings["system_test_password"])
+	}
+	if strings.Contains(body, "hunter2") {
+		t.Error("config contains the secure value")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	previous := otel.GetMeterProvider()
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+	provider, handler, err := metrics.Configure(context.Background(), config.Base{ServiceName: "kitchen"})
+	if err != nil {
+		t.Fatalf("metrics.Configure failed: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	// The scrape handler is served where the service registers it
+	s, server := newTestServer(t, service.Config{})
+	s.Handle("GET /metrics", handler)
+	code, body := get(t, server, "/metrics")
+	if code != http.StatusOK || !strings.Contains(body, `service_name="kitchen"`) {
+		t.Errorf("metrics = %d %q, want %d with the service metrics", code, body, http.StatusOK)
+	}
+}
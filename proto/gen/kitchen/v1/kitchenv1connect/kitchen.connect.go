@@ -38,19 +38,39 @@ const (
 	KitchenServiceCreatePostProcedure = "/kitchen.v1.KitchenService/CreatePost"
 	// KitchenServiceGetPostProcedure is the fully-qualified name of the KitchenService's GetPost RPC.
 	KitchenServiceGetPostProcedure = "/kitchen.v1.KitchenService/GetPost"
+	// KitchenServiceBatchGetPostsProcedure is the fully-qualified name of the KitchenService's
+	// BatchGetPosts RPC.
+	KitchenServiceBatchGetPostsProcedure = "/kitchen.v1.KitchenService/BatchGetPosts"
+	// KitchenServiceListPostsProcedure is the fully-qualified name of the KitchenService's ListPosts
+	// RPC.
+	KitchenServiceListPostsProcedure = "/kitchen.v1.KitchenService/ListPosts"
+	// KitchenServiceUpdatePostProcedure is the fully-qualified name of the KitchenService's UpdatePost
+	// RPC.
+	KitchenServiceUpdatePostProcedure = "/kitchen.v1.KitchenService/UpdatePost"
+	// KitchenServiceDeletePostProcedure is the fully-qualified name of the KitchenService's DeletePost
+	// RPC.
+	KitchenServiceDeletePostProcedure = "/kitchen.v1.KitchenService/DeletePost"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	kitchenServiceServiceDescriptor          = v1.File_kitchen_v1_kitchen_proto.Services().ByName("KitchenService")
-	kitchenServiceCreatePostMethodDescriptor = kitchenServiceServiceDescriptor.Methods().ByName("CreatePost")
-	kitchenServiceGetPostMethodDescriptor    = kitchenServiceServiceDescriptor.Methods().ByName("GetPost")
+	kitchenServiceServiceDescriptor             = v1.File_kitchen_v1_kitchen_proto.Services().ByName("KitchenService")
+	kitchenServiceCreatePostMethodDescriptor    = kitchenServiceServiceDescriptor.Methods().ByName("CreatePost")
+	kitchenServiceGetPostMethodDescriptor       = kitchenServiceServiceDescriptor.Methods().ByName("GetPost")
+	kitchenServiceBatchGetPostsMethodDescriptor = kitchenServiceServiceDescriptor.Methods().ByName("BatchGetPosts")
+	kitchenServiceListPostsMethodDescriptor     = kitchenServiceServiceDescriptor.Methods().ByName("ListPosts")
+	kitchenServiceUpdatePostMethodDescriptor    = kitchenServiceServiceDescriptor.Methods().ByName("UpdatePost")
+	kitchenServiceDeletePostMethodDescriptor    = kitchenServiceServiceDescriptor.Methods().ByName("DeletePost")
 )
 
 // KitchenServiceClient is a client for the kitchen.v1.KitchenService service.
 type KitchenServiceClient interface {
 	CreatePost(context.Context, *connect.Request[v1.CreatePostRequest]) (*connect.Response[v1.CreatePostResponse], error)
 	GetPost(context.Context, *connect.Request[v1.GetPostRequest]) (*connect.Response[v1.GetPostResponse], error)
+	BatchGetPosts(context.Context, *connect.Request[v1.BatchGetPostsRequest]) (*connect.Response[v1.BatchGetPostsResponse], error)
+	ListPosts(context.Context, *connect.Request[v1.ListPostsRequest]) (*connect.Response[v1.ListPostsResponse], error)
+	UpdatePost(context.Context, *connect.Request[v1.UpdatePostRequest]) (*connect.Response[v1.UpdatePostResponse], error)
+	DeletePost(context.Context, *connect.Request[v1.DeletePostRequest]) (*connect.Response[v1.DeletePostResponse], error)
 }
 
 // NewKitchenServiceClient constructs a client for the kitchen.v1.KitchenService service. By
@@ -75,13 +95,41 @@ func NewKitchenServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(kitchenServiceGetPostMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		batchGetPosts: connect.NewClient[v1.BatchGetPostsRequest, v1.BatchGetPostsResponse](
+			httpClient,
+			baseURL+KitchenServiceBatchGetPostsProcedure,
+			connect.WithSchema(kitchenServiceBatchGetPostsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listPosts: connect.NewClient[v1.ListPostsRequest, v1.ListPostsResponse](
+			httpClient,
+			baseURL+KitchenServiceListPostsProcedure,
+			connect.WithSchema(kitchenServiceListPostsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		updatePost: connect.NewClient[v1.UpdatePostRequest, v1.UpdatePostResponse](
+			httpClient,
+			baseURL+KitchenServiceUpdatePostProcedure,
+			connect.WithSchema(kitchenServiceUpdatePostMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deletePost: connect.NewClient[v1.DeletePostRequest, v1.DeletePostResponse](
+			httpClient,
+			baseURL+KitchenServiceDeletePostProcedure,
+			connect.WithSchema(kitchenServiceDeletePostMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // kitchenServiceClient implements KitchenServiceClient.
 type kitchenServiceClient struct {
-	createPost *connect.Client[v1.CreatePostRequest, v1.CreatePostResponse]
-	getPost    *connect.Client[v1.GetPostRequest, v1.GetPostResponse]
+	createPost    *connect.Client[v1.CreatePostRequest, v1.CreatePostResponse]
+	getPost       *connect.Client[v1.GetPostRequest, v1.GetPostResponse]
+	batchGetPosts *connect.Client[v1.BatchGetPostsRequest, v1.BatchGetPostsResponse]
+	listPosts     *connect.Client[v1.ListPostsRequest, v1.ListPostsResponse]
+	updatePost    *connect.Client[v1.UpdatePostRequest, v1.UpdatePostResponse]
+	deletePost    *connect.Client[v1.DeletePostRequest, v1.DeletePostResponse]
 }
 
 // CreatePost calls kitchen.v1.KitchenService.CreatePost.
@@ -94,10 +142,34 @@ func (c *kitchenServiceClient) GetPost(ctx context.Context, req *connect.Request
 	return c.getPost.CallUnary(ctx, req)
 }
 
+// BatchGetPosts calls kitchen.v1.KitchenService.BatchGetPosts.
+func (c *kitchenServiceClient) BatchGetPosts(ctx context.Context, req *connect.Request[v1.BatchGetPostsRequest]) (*connect.Response[v1.BatchGetPostsResponse], error) {
+	return c.batchGetPosts.CallUnary(ctx, req)
+}
+
+// ListPosts calls kitchen.v1.KitchenService.ListPosts.
+func (c *kitchenServiceClient) ListPosts(ctx context.Context, req *connect.Request[v1.ListPostsRequest]) (*connect.Response[v1.ListPostsResponse], error) {
+	return c.listPosts.CallUnary(ctx, req)
+}
+
+// UpdatePost calls kitchen.v1.KitchenService.UpdatePost.
+func (c *kitchenServiceClient) UpdatePost(ctx context.Context, req *connect.Request[v1.UpdatePostRequest]) (*connect.Response[v1.UpdatePostResponse], error) {
+	return c.updatePost.CallUnary(ctx, req)
+}
+
+// DeletePost calls kitchen.v1.KitchenService.DeletePost.
+func (c *kitchenServiceClient) DeletePost(ctx context.Context, req *connect.Request[v1.DeletePostRequest]) (*connect.Response[v1.DeletePostResponse], error) {
+	return c.deletePost.CallUnary(ctx, req)
+}
+
 // KitchenServiceHandler is an implementation of the kitchen.v1.KitchenService service.
 type KitchenServiceHandler interface {
 	CreatePost(context.Context, *connect.Request[v1.CreatePostRequest]) (*connect.Response[v1.CreatePostResponse], error)
 	GetPost(context.Context, *connect.Request[v1.GetPostRequest]) (*connect.Response[v1.GetPostResponse], error)
+	BatchGetPosts(context.Context, *connect.Request[v1.BatchGetPostsRequest]) (*connect.Response[v1.BatchGetPostsResponse], error)
+	ListPosts(context.Context, *connect.Request[v1.ListPostsRequest]) (*connect.Response[v1.ListPostsResponse], error)
+	UpdatePost(context.Context, *connect.Request[v1.UpdatePostRequest]) (*connect.Response[v1.UpdatePostResponse], error)
+	DeletePost(context.Context, *connect.Request[v1.DeletePostRequest]) (*connect.Response[v1.DeletePostResponse], error)
 }
 
 // NewKitchenServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -118,12 +190,44 @@ func NewKitchenServiceHandler(svc KitchenServiceHandler, opts ...connect.Handler
 		connect.WithSchema(kitchenServiceGetPostMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	kitchenServiceBatchGetPostsHandler := connect.NewUnaryHandler(
+		KitchenServiceBatchGetPostsProcedure,
+		svc.BatchGetPosts,
+		connect.WithSchema(kitchenServiceBatchGetPostsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	kitchenServiceListPostsHandler := connect.NewUnaryHandler(
+		KitchenServiceListPostsProcedure,
+		svc.ListPosts,
+		connect.WithSchema(kitchenServiceListPostsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	kitchenServiceUpdatePostHandler := connect.NewUnaryHandler(
+		KitchenServiceUpdatePostProcedure,
+		svc.UpdatePost,
+		connect.WithSchema(kitchenServiceUpdatePostMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	kitchenServiceDeletePostHandler := connect.NewUnaryHandler(
+		KitchenServiceDeletePostProcedure,
+		svc.DeletePost,
+		connect.WithSchema(kitchenServiceDeletePostMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/kitchen.v1.KitchenService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case KitchenServiceCreatePostProcedure:
 			kitchenServiceCreatePostHandler.ServeHTTP(w, r)
 		case KitchenServiceGetPostProcedure:
 			kitchenServiceGetPostHandler.ServeHTTP(w, r)
+		case KitchenServiceBatchGetPostsProcedure:
+			kitchenServiceBatchGetPostsHandler.ServeHTTP(w, r)
+		case KitchenServiceListPostsProcedure:
+			kitchenServiceListPostsHandler.ServeHTTP(w, r)
+		case KitchenServiceUpdatePostProcedure:
+			kitchenServiceUpdatePostHandler.ServeHTTP(w, r)
+		case KitchenServiceDeletePostProcedure:
+			kitchenServiceDeletePostHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -140,3 +244,19 @@ func (UnimplementedKitchenServiceHandler) CreatePost(context.Context, *connect.R
 func (UnimplementedKitchenServiceHandler) GetPost(context.Context, *connect.Request[v1.GetPostRequest]) (*connect.Response[v1.GetPostResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("kitchen.v1.KitchenService.GetPost is not implemented"))
 }
+
+func (UnimplementedKitchenServiceHandler) BatchGetPosts(context.Context, *connect.Request[v1.BatchGetPostsRequest]) (*connect.Response[v1.BatchGetPostsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("kitchen.v1.KitchenService.BatchGetPosts is not implemented"))
+}
+
+func (UnimplementedKitchenServiceHandler) ListPosts(context.Context, *connect.Request[v1.ListPostsRequest]) (*connect.Response[v1.ListPostsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("kitchen.v1.KitchenService.ListPosts is not implemented"))
+}
+
+func (UnimplementedKitchenServiceHandler) UpdatePost(context.Context, *connect.Request[v1.UpdatePostRequest]) (*connect.Response[v1.UpdatePostResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("kitchen.v1.KitchenService.UpdatePost is not implemented"))
+}
+
+func (UnimplementedKitchenServiceHandler) DeletePost(context.Context, *connect.Request[v1.DeletePostRequest]) (*connect.Response[v1.DeletePostResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("kitchen.v1.KitchenService.DeletePost is not implemented"))
+}
@@ -0,0 +1,28 @@
+package kitchen
+
+import (
+	"context"
+	"fmt"
+	"kitchen/internal/store"
+	"kitchen/internal/store/dynamo"
+	"kitchen/internal/store/memory"
+	"kitchen/internal/store/sqlite"
+)
+
+// NewStore creates the store.Store selected by the config
+func NewStore(ctx context.Context, cfg Config) (store.Store, error) {
+	switch cfg.StoreBackend {
+	case StoreBackendMemory:
+		return memory.NewStore(), nil
+	case StoreBackendDynamo:
+		client, err := dynamo.NewClient(ctx, cfg.Dynamo)
+		if err != nil {
+			return nil, err
+		}
+		return dynamo.NewStore(client, cfg.Dynamo), nil
+	case StoreBackendSQLite:
+		return sqlite.Open(cfg.SQLite)
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", cfg.StoreBackend)
+	}
+}